@@ -0,0 +1,115 @@
+package v1
+
+import (
+	v1Client "deployment-service/apps/dao/client/v1"
+	"deployment-service/apps/repository/adapter"
+	model_config "deployment-service/models/model.config"
+	"deployment-service/utils"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigController struct {
+	v1ConfigDao v1Client.IConfigDao
+}
+
+type IConfigController interface {
+	ApplySecret(ctx *gin.Context)
+	ListSecrets(ctx *gin.Context)
+	DeleteSecret(ctx *gin.Context)
+	ApplyConfigMap(ctx *gin.Context)
+	ListConfigMaps(ctx *gin.Context)
+	DeleteConfigMap(ctx *gin.Context)
+	ApplyRegistryCredential(ctx *gin.Context)
+	ListRegistryCredentials(ctx *gin.Context)
+	DeleteRegistryCredential(ctx *gin.Context)
+}
+
+func NewConfigController(repository *adapter.Repository) IConfigController {
+	return &ConfigController{
+		v1ConfigDao: v1Client.NewConfigDao(repository),
+	}
+}
+
+// bindTenantConfig binds and checks the body shared by the secret and config map apis
+func bindTenantConfig(ctx *gin.Context) (*model_config.TenantConfigReq, bool) {
+	var request *model_config.TenantConfigReq
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return nil, false
+	}
+	if request.Name == "" || len(request.Data) == 0 {
+		ctx.JSON(400, gin.H{
+			"error": "Invalid request body. Please provide all required fields.",
+		})
+		ctx.Abort()
+		return nil, false
+	}
+	return request, true
+}
+
+func (ctrl ConfigController) ApplySecret(ctx *gin.Context) {
+	fmt.Println("applying secret")
+	request, ok := bindTenantConfig(ctx)
+	if !ok {
+		return
+	}
+	ctrl.v1ConfigDao.ApplySecret(ctx, ctx.GetString("username"), request)
+}
+
+func (ctrl ConfigController) ListSecrets(ctx *gin.Context) {
+	fmt.Println("getting secrets")
+	ctrl.v1ConfigDao.ListSecrets(ctx, ctx.GetString("username"))
+}
+
+func (ctrl ConfigController) DeleteSecret(ctx *gin.Context) {
+	fmt.Println("deleting secret by name")
+	ctrl.v1ConfigDao.DeleteSecret(ctx, ctx.GetString("username"), ctx.Param("secret_name"))
+}
+
+func (ctrl ConfigController) ApplyConfigMap(ctx *gin.Context) {
+	fmt.Println("applying config map")
+	request, ok := bindTenantConfig(ctx)
+	if !ok {
+		return
+	}
+	ctrl.v1ConfigDao.ApplyConfigMap(ctx, ctx.GetString("username"), request)
+}
+
+func (ctrl ConfigController) ListConfigMaps(ctx *gin.Context) {
+	fmt.Println("getting config maps")
+	ctrl.v1ConfigDao.ListConfigMaps(ctx, ctx.GetString("username"))
+}
+
+func (ctrl ConfigController) DeleteConfigMap(ctx *gin.Context) {
+	fmt.Println("deleting config map by name")
+	ctrl.v1ConfigDao.DeleteConfigMap(ctx, ctx.GetString("username"), ctx.Param("config_map_name"))
+}
+
+func (ctrl ConfigController) ApplyRegistryCredential(ctx *gin.Context) {
+	fmt.Println("applying registry credential")
+	var request *model_config.RegistryCredentialReq
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return
+	}
+	if request.Name == "" || request.Server == "" || request.Username == "" || request.Password == "" {
+		ctx.JSON(400, gin.H{
+			"error": "Invalid request body. Please provide all required fields.",
+		})
+		ctx.Abort()
+		return
+	}
+	ctrl.v1ConfigDao.ApplyRegistryCredential(ctx, ctx.GetString("username"), request)
+}
+
+func (ctrl ConfigController) ListRegistryCredentials(ctx *gin.Context) {
+	fmt.Println("getting registry credentials")
+	ctrl.v1ConfigDao.ListRegistryCredentials(ctx, ctx.GetString("username"))
+}
+
+func (ctrl ConfigController) DeleteRegistryCredential(ctx *gin.Context) {
+	fmt.Println("deleting registry credential by name")
+	ctrl.v1ConfigDao.DeleteRegistryCredential(ctx, ctx.GetString("username"), ctx.Param("registry_name"))
+}
@@ -3,9 +3,11 @@ package v1
 import (
 	v1Client "deployment-service/apps/dao/client/v1"
 	"deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
 	model_deployment "deployment-service/models/model.deployment"
 	"deployment-service/utils"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +26,35 @@ type IDeploymentController interface {
 	DeleteDeployment(ctx *gin.Context)
 	GetLatestEvents(ctx *gin.Context)
 	UpdateDeploymentByName(ctx *gin.Context)
+	GetRolloutStatus(ctx *gin.Context)
+	GetDeploymentRevisions(ctx *gin.Context)
+	GetDeploymentPods(ctx *gin.Context)
+	RestartDeployment(ctx *gin.Context)
+	DeleteDeploymentPod(ctx *gin.Context)
+	RollbackDeployment(ctx *gin.Context)
+	GetDeploymentLogs(ctx *gin.Context)
+	CreateAutoscaler(ctx *gin.Context)
+	UpdateAutoscaler(ctx *gin.Context)
+	GetAutoscaler(ctx *gin.Context)
+	DeleteAutoscaler(ctx *gin.Context)
+	GetCanary(ctx *gin.Context)
+	PromoteCanary(ctx *gin.Context)
+	AbortCanary(ctx *gin.Context)
+	GetBlueGreen(ctx *gin.Context)
+	SwitchBackBlueGreen(ctx *gin.Context)
+	CreateSchedule(ctx *gin.Context)
+	GetSchedules(ctx *gin.Context)
+	UpdateSchedule(ctx *gin.Context)
+	DeleteSchedule(ctx *gin.Context)
+	GetScheduleNextRuns(ctx *gin.Context)
+	GetScheduleRuns(ctx *gin.Context)
+	PauseDeployment(ctx *gin.Context)
+	ResumeDeployment(ctx *gin.Context)
+	GetDrift(ctx *gin.Context)
+	ReconcileNamespace(ctx *gin.Context)
+	GetDeploymentOperations(ctx *gin.Context)
+	GetDeploymentHistory(ctx *gin.Context)
+	ApplyDeploymentSpec(ctx *gin.Context)
 }
 
 func NewDeploymentController(repository *adapter.Repository) IDeploymentController {
@@ -68,6 +99,49 @@ func (ctrl DeploymentController) CreateDeployment(ctx *gin.Context) {
 			"error": "Invalid request body. Please provide all required fields.",
 		})
 		ctx.Abort()
+		return
+	}
+	if ok := validateResources(ctx, request.Resources, model_deployment.DefaultDeploymentResources()); !ok {
+		return
+	}
+	if err := model_deployment.ValidateEnv(request.Env); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid env. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if request.Probes != nil {
+		if err := request.Probes.WithDefaults(request.ContainerPort).Validate(); err != nil {
+			ctx.JSON(400, gin.H{
+				"error": fmt.Sprintf("Invalid probes. %s", err.Error()),
+			})
+			ctx.Abort()
+			return
+		}
+	}
+	if request.Exposure != nil {
+		if err := request.Exposure.Validate(); err != nil {
+			ctx.JSON(400, gin.H{
+				"error": fmt.Sprintf("Invalid exposure. %s", err.Error()),
+			})
+			ctx.Abort()
+			return
+		}
+	}
+	if err := model_deployment.ValidateContainers(request.Name, request.Sidecars, request.InitContainers); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid containers. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if err := model_deployment.ValidateNetworkAccess(request.NetworkAccess); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid network access. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
 	}
 	request.Namespace = ctx.GetString("username")
 	request.CreatedAt = time.Now()
@@ -81,7 +155,31 @@ func (ctrl DeploymentController) DeleteDeployment(ctx *gin.Context) {
 }
 
 func (ctrl DeploymentController) GetLatestEvents(ctx *gin.Context) {
-	ctrl.v1DeploymentsDao.GetLatestEvents(ctx, ctx.GetString("username"), 10)
+	var query = &model_deployment.EventsQuery{}
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid query parameters. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if query.Type != "" && query.Type != "Normal" && query.Type != "Warning" {
+		ctx.JSON(400, gin.H{
+			"error": "Invalid query parameters. type must be Normal or Warning.",
+		})
+		ctx.Abort()
+		return
+	}
+	if query.Since != "" {
+		if _, err := time.Parse(time.RFC3339, query.Since); err != nil {
+			ctx.JSON(400, gin.H{
+				"error": "Invalid query parameters. since must be an RFC3339 timestamp.",
+			})
+			ctx.Abort()
+			return
+		}
+	}
+	ctrl.v1DeploymentsDao.GetLatestEvents(ctx, ctx.GetString("username"), query)
 }
 
 func (ctrl DeploymentController) UpdateDeploymentByName(ctx *gin.Context) {
@@ -111,6 +209,338 @@ func (ctrl DeploymentController) UpdateDeploymentByName(ctx *gin.Context) {
 		ctx.Abort()
 		return
 	}
+	if err := model_deployment.ValidateEnv(request.Env); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid env. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if request.Probes != nil {
+		if err := request.Probes.Validate(); err != nil {
+			ctx.JSON(400, gin.H{
+				"error": fmt.Sprintf("Invalid probes. %s", err.Error()),
+			})
+			ctx.Abort()
+			return
+		}
+	}
+	if err := model_deployment.ValidateNetworkAccess(request.NetworkAccess); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid network access. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if request.Canary != nil && (request.Image == "" || request.Canary.Replicas < 0) {
+		ctx.JSON(400, gin.H{
+			"error": "Invalid canary. A canary needs an image and a non negative number of replicas.",
+		})
+		ctx.Abort()
+		return
+	}
+	if request.BlueGreen != nil && (request.Image == "" || request.BlueGreen.RetentionSeconds < 0 || request.Canary != nil) {
+		ctx.JSON(400, gin.H{
+			"error": "Invalid blue/green release. It needs an image, a non negative retention and can't be combined with a canary.",
+		})
+		ctx.Abort()
+		return
+	}
 	fmt.Println("ctrrl update deployment by name")
 	ctrl.v1DeploymentsDao.UpdateDeploymentByName(ctx, ctx.GetString("username"), request)
 }
+
+func (ctrl DeploymentController) GetRolloutStatus(ctx *gin.Context) {
+	fmt.Println("getting rollout status by name")
+	ctrl.v1DeploymentsDao.GetRolloutStatus(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) GetDeploymentRevisions(ctx *gin.Context) {
+	fmt.Println("getting deployment revisions by name")
+	ctrl.v1DeploymentsDao.GetDeploymentRevisions(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) GetDeploymentPods(ctx *gin.Context) {
+	fmt.Println("getting deployment pods by name")
+	ctrl.v1DeploymentsDao.GetDeploymentPods(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) DeleteDeploymentPod(ctx *gin.Context) {
+	fmt.Println("deleting deployment pod by name")
+	ctrl.v1DeploymentsDao.DeleteDeploymentPod(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), ctx.Param("pod_name"))
+}
+
+func (ctrl DeploymentController) RestartDeployment(ctx *gin.Context) {
+	fmt.Println("restarting deployment by name")
+	ctrl.v1DeploymentsDao.RestartDeployment(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) RollbackDeployment(ctx *gin.Context) {
+	fmt.Println("rolling back deployment by name")
+	var request = &model_deployment.RollbackDeploymentReq{}
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return
+	}
+	if request.Revision < 0 {
+		ctx.JSON(400, gin.H{
+			"error": "Invalid request body. Revision must be 0 (previous revision) or a positive revision number.",
+		})
+		ctx.Abort()
+		return
+	}
+	ctrl.v1DeploymentsDao.RollbackDeployment(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), request)
+}
+
+// validateResources rejects resources kubernetes can't apply before they reach the service. The quantities are
+// checked once merged with base, the resources the container falls back to for anything the request leaves out.
+func validateResources(ctx *gin.Context, resources *model_deployment.DeploymentResources, base model_deployment.DeploymentResources) bool {
+	if resources == nil {
+		return true
+	}
+	if _, err := resources.Merge(base).ResourceRequirements(); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid resources. %s", err.Error()),
+		})
+		ctx.Abort()
+		return false
+	}
+	return true
+}
+
+func (ctrl DeploymentController) GetDeploymentLogs(ctx *gin.Context) {
+	fmt.Println("getting deployment logs by name")
+	var query = &model_deployment.LogsQuery{}
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid query parameters. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if (query.TailLines != nil && *query.TailLines < 0) || (query.SinceSeconds != nil && *query.SinceSeconds <= 0) {
+		ctx.JSON(400, gin.H{
+			"error": "Invalid query parameters. tailLines must not be negative and sinceSeconds must be positive.",
+		})
+		ctx.Abort()
+		return
+	}
+	ctrl.v1DeploymentsDao.GetDeploymentLogs(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), query)
+}
+
+// bindAutoscalerConfig binds and validates the body of the autoscaler apis
+func bindAutoscalerConfig(ctx *gin.Context) (*model_deployment.AutoscalerConfig, bool) {
+	var request *model_deployment.AutoscalerConfig
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return nil, false
+	}
+	if err := request.Validate(); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid autoscaler. %s", err.Error()),
+		})
+		ctx.Abort()
+		return nil, false
+	}
+	return request, true
+}
+
+func (ctrl DeploymentController) CreateAutoscaler(ctx *gin.Context) {
+	fmt.Println("creating autoscaler for deployment")
+	request, ok := bindAutoscalerConfig(ctx)
+	if !ok {
+		return
+	}
+	ctrl.v1DeploymentsDao.CreateAutoscaler(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), request)
+}
+
+func (ctrl DeploymentController) UpdateAutoscaler(ctx *gin.Context) {
+	fmt.Println("updating autoscaler for deployment")
+	request, ok := bindAutoscalerConfig(ctx)
+	if !ok {
+		return
+	}
+	ctrl.v1DeploymentsDao.UpdateAutoscaler(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), request)
+}
+
+func (ctrl DeploymentController) GetAutoscaler(ctx *gin.Context) {
+	fmt.Println("getting autoscaler for deployment")
+	ctrl.v1DeploymentsDao.GetAutoscaler(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) DeleteAutoscaler(ctx *gin.Context) {
+	fmt.Println("deleting autoscaler for deployment")
+	ctrl.v1DeploymentsDao.DeleteAutoscaler(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) GetCanary(ctx *gin.Context) {
+	fmt.Println("getting canary by deployment name")
+	ctrl.v1DeploymentsDao.GetCanary(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) PromoteCanary(ctx *gin.Context) {
+	fmt.Println("promoting canary by deployment name")
+	ctrl.v1DeploymentsDao.PromoteCanary(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) AbortCanary(ctx *gin.Context) {
+	fmt.Println("aborting canary by deployment name")
+	ctrl.v1DeploymentsDao.AbortCanary(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) GetBlueGreen(ctx *gin.Context) {
+	fmt.Println("getting blue/green release by deployment name")
+	ctrl.v1DeploymentsDao.GetBlueGreen(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) SwitchBackBlueGreen(ctx *gin.Context) {
+	fmt.Println("switching back blue/green release by deployment name")
+	ctrl.v1DeploymentsDao.SwitchBackBlueGreen(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func bindScheduleReq(ctx *gin.Context) (*model_deployment.ScheduleReq, bool) {
+	var request *model_deployment.ScheduleReq
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return nil, false
+	}
+	if err := request.Validate(); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid schedule. %s", err.Error()),
+		})
+		ctx.Abort()
+		return nil, false
+	}
+	return request, true
+}
+
+func (ctrl DeploymentController) CreateSchedule(ctx *gin.Context) {
+	fmt.Println("creating schedule for deployment")
+	request, ok := bindScheduleReq(ctx)
+	if !ok {
+		return
+	}
+	ctrl.v1DeploymentsDao.CreateSchedule(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), request)
+}
+
+func (ctrl DeploymentController) GetSchedules(ctx *gin.Context) {
+	fmt.Println("getting schedules by deployment name")
+	ctrl.v1DeploymentsDao.GetSchedules(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) UpdateSchedule(ctx *gin.Context) {
+	fmt.Println("updating schedule of deployment")
+	request, ok := bindScheduleReq(ctx)
+	if !ok {
+		return
+	}
+	ctrl.v1DeploymentsDao.UpdateSchedule(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), ctx.Param("schedule_id"), request)
+}
+
+func (ctrl DeploymentController) DeleteSchedule(ctx *gin.Context) {
+	fmt.Println("deleting schedule of deployment")
+	ctrl.v1DeploymentsDao.DeleteSchedule(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), ctx.Param("schedule_id"))
+}
+
+func (ctrl DeploymentController) GetScheduleNextRuns(ctx *gin.Context) {
+	fmt.Println("previewing next runs of schedule")
+	count := constants.SCHEDULE_PREVIEW_DEFAULT_RUNS
+	if value := ctx.Query("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > constants.SCHEDULE_PREVIEW_MAX_RUNS {
+			ctx.JSON(400, gin.H{
+				"error": fmt.Sprintf("Invalid count. It must be between 1 and %d.", constants.SCHEDULE_PREVIEW_MAX_RUNS),
+			})
+			ctx.Abort()
+			return
+		}
+		count = parsed
+	}
+	ctrl.v1DeploymentsDao.GetScheduleNextRuns(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), ctx.Param("schedule_id"), count)
+}
+
+func (ctrl DeploymentController) GetScheduleRuns(ctx *gin.Context) {
+	fmt.Println("getting runs of schedule")
+	ctrl.v1DeploymentsDao.GetScheduleRuns(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), ctx.Param("schedule_id"))
+}
+
+func (ctrl DeploymentController) PauseDeployment(ctx *gin.Context) {
+	fmt.Println("pausing deployment by name")
+	ctrl.v1DeploymentsDao.PauseDeployment(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) ResumeDeployment(ctx *gin.Context) {
+	fmt.Println("resuming deployment by name")
+	ctrl.v1DeploymentsDao.ResumeDeployment(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) GetDrift(ctx *gin.Context) {
+	fmt.Println("getting drift between records and cluster")
+	ctrl.v1DeploymentsDao.GetDrift(ctx, ctx.GetString("username"))
+}
+
+func (ctrl DeploymentController) ReconcileNamespace(ctx *gin.Context) {
+	fmt.Println("reconciling records with cluster")
+	ctrl.v1DeploymentsDao.ReconcileNamespace(ctx, ctx.GetString("username"))
+}
+
+func (ctrl DeploymentController) GetDeploymentOperations(ctx *gin.Context) {
+	fmt.Println("getting create and delete operations by deployment name")
+	ctrl.v1DeploymentsDao.GetDeploymentOperations(ctx, ctx.GetString("username"), ctx.Param("deployment_name"))
+}
+
+func (ctrl DeploymentController) GetDeploymentHistory(ctx *gin.Context) {
+	fmt.Println("getting change history by deployment name")
+	var query = &model_deployment.HistoryQuery{}
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid query parameters. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	ctrl.v1DeploymentsDao.GetDeploymentHistory(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), query)
+}
+
+func (ctrl DeploymentController) ApplyDeploymentSpec(ctx *gin.Context) {
+	fmt.Println("applying deployment spec by name")
+	var request = &model_deployment.DeploymentSpecReq{}
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return
+	}
+	if err := request.Validate(); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid spec. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if ok := validateResources(ctx, request.Resources, model_deployment.DefaultDeploymentResources()); !ok {
+		return
+	}
+	if err := model_deployment.ValidateEnv(request.Env); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid env. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	if request.Probes != nil {
+		if err := request.Probes.Validate(); err != nil {
+			ctx.JSON(400, gin.H{
+				"error": fmt.Sprintf("Invalid probes. %s", err.Error()),
+			})
+			ctx.Abort()
+			return
+		}
+	}
+	if err := model_deployment.ValidateContainers(ctx.Param("deployment_name"), request.Sidecars, request.InitContainers); err != nil {
+		ctx.JSON(400, gin.H{
+			"error": fmt.Sprintf("Invalid containers. %s", err.Error()),
+		})
+		ctx.Abort()
+		return
+	}
+	ctrl.v1DeploymentsDao.ApplyDeploymentSpec(ctx, ctx.GetString("username"), ctx.Param("deployment_name"), request)
+}
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	v1Internal "deployment-service/apps/dao/private/v1"
+	"deployment-service/apps/repository/adapter"
+	model_tenant "deployment-service/models/model.tenant"
+	"deployment-service/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenantController struct {
+	v1TenantDao v1Internal.ITenantDao
+}
+
+type ITenantController interface {
+	UpdateTenantQuota(ctx *gin.Context)
+	UpdateReconcilePolicy(ctx *gin.Context)
+}
+
+func NewTenantController(repository *adapter.Repository) ITenantController {
+	return &TenantController{
+		v1TenantDao: v1Internal.NewTenantDao(repository),
+	}
+}
+
+func (ctrl TenantController) UpdateTenantQuota(ctx *gin.Context) {
+	namespace := ctx.Param("namespace")
+	if namespace == "" {
+		ctx.JSON(400, gin.H{"error": "namespace is required"})
+		ctx.Abort()
+		return
+	}
+	var request model_tenant.UpdateTenantQuotaReq
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return
+	}
+	if request.Plan != "" {
+		if _, ok := model_tenant.TENANT_PLANS[request.Plan]; !ok {
+			ctx.JSON(400, gin.H{"error": "unknown tenant plan " + request.Plan})
+			ctx.Abort()
+			return
+		}
+	}
+	ctrl.v1TenantDao.UpdateTenantQuota(ctx, namespace, request)
+}
+
+func (ctrl TenantController) UpdateReconcilePolicy(ctx *gin.Context) {
+	namespace := ctx.Param("namespace")
+	if namespace == "" {
+		ctx.JSON(400, gin.H{"error": "namespace is required"})
+		ctx.Abort()
+		return
+	}
+	var request model_tenant.UpdateReconcilePolicyReq
+	if ok := utils.BindJSON(ctx, &request); !ok {
+		ctx.Abort()
+		return
+	}
+	if err := model_tenant.ValidateReconcilePolicy(request.Policy); err != nil {
+		ctx.JSON(400, gin.H{"error": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctrl.v1TenantDao.UpdateReconcilePolicy(ctx, namespace, request)
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"deployment-service/apps/repository/adapter"
+	"deployment-service/apps/svc"
+	model_config "deployment-service/models/model.config"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigDao struct {
+	ServiceRepo *svc.ServiceRepository
+}
+
+type IConfigDao interface {
+	ApplySecret(ctx *gin.Context, namespace string, payload *model_config.TenantConfigReq)
+	ListSecrets(ctx *gin.Context, namespace string)
+	DeleteSecret(ctx *gin.Context, namespace, secretName string)
+	ApplyConfigMap(ctx *gin.Context, namespace string, payload *model_config.TenantConfigReq)
+	ListConfigMaps(ctx *gin.Context, namespace string)
+	DeleteConfigMap(ctx *gin.Context, namespace, configMapName string)
+	ApplyRegistryCredential(ctx *gin.Context, namespace string, payload *model_config.RegistryCredentialReq)
+	ListRegistryCredentials(ctx *gin.Context, namespace string)
+	DeleteRegistryCredential(ctx *gin.Context, namespace, name string)
+}
+
+func NewConfigDao(repository *adapter.Repository) IConfigDao {
+	return &ConfigDao{
+		ServiceRepo: svc.NewServiceRepo(repository),
+	}
+}
+
+func (dao ConfigDao) ApplySecret(ctx *gin.Context, namespace string, payload *model_config.TenantConfigReq) {
+	err := dao.ServiceRepo.ConfigService.ApplySecret(namespace, *payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Applied Secret: %s", payload.Name)})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) ListSecrets(ctx *gin.Context, namespace string) {
+	response, err := dao.ServiceRepo.ConfigService.ListSecrets(namespace)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) DeleteSecret(ctx *gin.Context, namespace, secretName string) {
+	err := dao.ServiceRepo.ConfigService.DeleteSecret(namespace, secretName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Deleted Secret: %s", secretName)})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) ApplyConfigMap(ctx *gin.Context, namespace string, payload *model_config.TenantConfigReq) {
+	err := dao.ServiceRepo.ConfigService.ApplyConfigMap(namespace, *payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Applied Config Map: %s", payload.Name)})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) ListConfigMaps(ctx *gin.Context, namespace string) {
+	response, err := dao.ServiceRepo.ConfigService.ListConfigMaps(namespace)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) DeleteConfigMap(ctx *gin.Context, namespace, configMapName string) {
+	err := dao.ServiceRepo.ConfigService.DeleteConfigMap(namespace, configMapName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Deleted Config Map: %s", configMapName)})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) ApplyRegistryCredential(ctx *gin.Context, namespace string, payload *model_config.RegistryCredentialReq) {
+	err := dao.ServiceRepo.ConfigService.ApplyRegistryCredential(namespace, *payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Applied Registry Credential: %s", payload.Name)})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) ListRegistryCredentials(ctx *gin.Context, namespace string) {
+	response, err := dao.ServiceRepo.ConfigService.ListRegistryCredentials(namespace)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao ConfigDao) DeleteRegistryCredential(ctx *gin.Context, namespace, name string) {
+	err := dao.ServiceRepo.ConfigService.DeleteRegistryCredential(namespace, name)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Deleted Registry Credential: %s", name)})
+	ctx.Abort()
+}
@@ -4,10 +4,13 @@ import (
 	"deployment-service/apps/repository/adapter"
 	"deployment-service/apps/svc"
 	model_deployment "deployment-service/models/model.deployment"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	"gorm.io/gorm/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,8 +26,37 @@ type IDeploymentsDao interface {
 	CreateNamespace(ctx *gin.Context, namespace string)
 	CreateDeployment(ctx *gin.Context, payload *model_deployment.CreateDeploymentRequest)
 	DeleteDeployment(ctx *gin.Context, namespace string, deploymentName string)
-	GetLatestEvents(ctx *gin.Context, namespace string, topK int)
+	GetLatestEvents(ctx *gin.Context, namespace string, query *model_deployment.EventsQuery)
 	UpdateDeploymentByName(ctx *gin.Context, namespace string, payload *model_deployment.UpdateDeploymentReq)
+	GetRolloutStatus(ctx *gin.Context, namespace, deploymentName string)
+	GetDeploymentRevisions(ctx *gin.Context, namespace, deploymentName string)
+	GetDeploymentPods(ctx *gin.Context, namespace, deploymentName string)
+	RestartDeployment(ctx *gin.Context, namespace, deploymentName string)
+	DeleteDeploymentPod(ctx *gin.Context, namespace, deploymentName, podName string)
+	RollbackDeployment(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.RollbackDeploymentReq)
+	GetDeploymentLogs(ctx *gin.Context, namespace, deploymentName string, query *model_deployment.LogsQuery)
+	CreateAutoscaler(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.AutoscalerConfig)
+	UpdateAutoscaler(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.AutoscalerConfig)
+	GetAutoscaler(ctx *gin.Context, namespace, deploymentName string)
+	DeleteAutoscaler(ctx *gin.Context, namespace, deploymentName string)
+	GetCanary(ctx *gin.Context, namespace, deploymentName string)
+	PromoteCanary(ctx *gin.Context, namespace, deploymentName string)
+	AbortCanary(ctx *gin.Context, namespace, deploymentName string)
+	GetBlueGreen(ctx *gin.Context, namespace, deploymentName string)
+	SwitchBackBlueGreen(ctx *gin.Context, namespace, deploymentName string)
+	CreateSchedule(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.ScheduleReq)
+	GetSchedules(ctx *gin.Context, namespace, deploymentName string)
+	UpdateSchedule(ctx *gin.Context, namespace, deploymentName, scheduleID string, payload *model_deployment.ScheduleReq)
+	DeleteSchedule(ctx *gin.Context, namespace, deploymentName, scheduleID string)
+	GetScheduleNextRuns(ctx *gin.Context, namespace, deploymentName, scheduleID string, count int)
+	GetScheduleRuns(ctx *gin.Context, namespace, deploymentName, scheduleID string)
+	PauseDeployment(ctx *gin.Context, namespace, deploymentName string)
+	ResumeDeployment(ctx *gin.Context, namespace, deploymentName string)
+	GetDrift(ctx *gin.Context, namespace string)
+	ReconcileNamespace(ctx *gin.Context, namespace string)
+	GetDeploymentOperations(ctx *gin.Context, namespace, deploymentName string)
+	GetDeploymentHistory(ctx *gin.Context, namespace, deploymentName string, query *model_deployment.HistoryQuery)
+	ApplyDeploymentSpec(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.DeploymentSpecReq)
 }
 
 func NewDeploymentsDao(repository *adapter.Repository) IDeploymentsDao {
@@ -33,8 +65,17 @@ func NewDeploymentsDao(repository *adapter.Repository) IDeploymentsDao {
 	}
 }
 
-func (dao DeploymentDao) GetLatestEvents(ctx *gin.Context, namespace string, topK int) {
-	events, err := dao.ServiceRepo.DeploymentService.GetLatestEvents(namespace, topK)
+// requestActor is who a change is recorded for in the deployment history, as identified by the auth middleware
+func requestActor(ctx *gin.Context) model_deployment.Actor {
+	return model_deployment.Actor{
+		Name:      ctx.GetString("actor"),
+		SourceIP:  ctx.ClientIP(),
+		RequestID: ctx.GetString("request_id"),
+	}
+}
+
+func (dao DeploymentDao) GetLatestEvents(ctx *gin.Context, namespace string, query *model_deployment.EventsQuery) {
+	events, err := dao.ServiceRepo.DeploymentService.GetLatestEvents(namespace, *query)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
 		ctx.Abort()
@@ -97,7 +138,7 @@ func (dao DeploymentDao) CreateNamespace(ctx *gin.Context, namespace string) {
 }
 
 func (dao DeploymentDao) CreateDeployment(ctx *gin.Context, payload *model_deployment.CreateDeploymentRequest) {
-	resp, err := dao.ServiceRepo.DeploymentService.CreateDeployment(payload)
+	resp, err := dao.ServiceRepo.DeploymentService.CreateDeployment(payload, requestActor(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
 		ctx.Abort()
@@ -116,7 +157,7 @@ func (dao DeploymentDao) DeleteDeployment(ctx *gin.Context, namespace, deploymen
 		ctx.Abort()
 		return
 	}
-	_, err = dao.ServiceRepo.DeploymentService.DeleteDeployment(namespace, deployment_name, deployment_info.RepoScoutId)
+	_, err = dao.ServiceRepo.DeploymentService.DeleteDeployment(namespace, deployment_name, deployment_info.RepoScoutId, requestActor(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
 		ctx.Abort()
@@ -128,7 +169,13 @@ func (dao DeploymentDao) DeleteDeployment(ctx *gin.Context, namespace, deploymen
 
 func (dao DeploymentDao) UpdateDeploymentByName(ctx *gin.Context, namespace string, payload *model_deployment.UpdateDeploymentReq) {
 	fmt.Println("updateing deployment ")
-	resp, err := dao.ServiceRepo.DeploymentService.UpdateDeploymentByName(namespace, payload.Name, payload.Image, payload.Replicas)
+	resp, err := dao.ServiceRepo.DeploymentService.UpdateDeploymentByName(namespace, payload, requestActor(ctx))
+	var validationErr *svc.ValidationError
+	if errors.As(err, &validationErr) {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"message": validationErr.Error()})
+		ctx.Abort()
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
 		ctx.Abort()
@@ -139,3 +186,387 @@ func (dao DeploymentDao) UpdateDeploymentByName(ctx *gin.Context, namespace stri
 		"result":  resp})
 	ctx.Abort()
 }
+
+func (dao DeploymentDao) GetRolloutStatus(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetRolloutStatus(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetDeploymentRevisions(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetDeploymentRevisions(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetDeploymentPods(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetDeploymentPods(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) DeleteDeploymentPod(ctx *gin.Context, namespace, deploymentName, podName string) {
+	response, err := dao.ServiceRepo.DeploymentService.DeleteDeploymentPod(namespace, deploymentName, podName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) RollbackDeployment(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.RollbackDeploymentReq) {
+	resp, err := dao.ServiceRepo.DeploymentService.RollbackDeployment(namespace, deploymentName, payload.Revision, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Rolled Back Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) RestartDeployment(ctx *gin.Context, namespace, deploymentName string) {
+	resp, err := dao.ServiceRepo.DeploymentService.RestartDeployment(namespace, deploymentName, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Restarted Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetDeploymentLogs(ctx *gin.Context, namespace, deploymentName string, query *model_deployment.LogsQuery) {
+	// The request context is cancelled when the client disconnects, which closes the pod log streams
+	lines, err := dao.ServiceRepo.DeploymentService.StreamDeploymentLogs(ctx.Request.Context(), namespace, deploymentName, *query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+
+	if !query.Follow {
+		response := []model_deployment.LogLine{}
+		for line := range lines {
+			response = append(response, line)
+		}
+		ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+		ctx.Abort()
+		return
+	}
+
+	sse := query.Format == "sse" || ctx.GetHeader("Accept") == "text/event-stream"
+	if sse {
+		ctx.Header("Content-Type", "text/event-stream")
+	} else {
+		ctx.Header("Content-Type", "text/plain; charset=utf-8")
+	}
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Stream(func(w io.Writer) bool {
+		line, ok := <-lines
+		if !ok {
+			return false
+		}
+		if sse {
+			if line.Error != "" {
+				ctx.SSEvent("error", line)
+			} else {
+				ctx.SSEvent("log", line)
+			}
+		} else if line.Error != "" {
+			fmt.Fprintf(w, "[%s/%s] error: %s\n", line.Pod, line.Container, line.Error)
+		} else {
+			fmt.Fprintf(w, "[%s/%s] %s\n", line.Pod, line.Container, line.Line)
+		}
+		return true
+	})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) CreateAutoscaler(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.AutoscalerConfig) {
+	resp, err := dao.ServiceRepo.DeploymentService.CreateAutoscaler(namespace, deploymentName, *payload, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Created Autoscaler for Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) UpdateAutoscaler(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.AutoscalerConfig) {
+	resp, err := dao.ServiceRepo.DeploymentService.UpdateAutoscaler(namespace, deploymentName, *payload, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Updated Autoscaler for Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetAutoscaler(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetAutoscaler(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) DeleteAutoscaler(ctx *gin.Context, namespace, deploymentName string) {
+	_, err := dao.ServiceRepo.DeploymentService.DeleteAutoscaler(namespace, deploymentName, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Deleted Autoscaler for Deployment: %s", deploymentName)})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetCanary(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetCanary(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) PromoteCanary(ctx *gin.Context, namespace, deploymentName string) {
+	resp, err := dao.ServiceRepo.DeploymentService.PromoteCanary(namespace, deploymentName, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Promoted Canary of Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) AbortCanary(ctx *gin.Context, namespace, deploymentName string) {
+	_, err := dao.ServiceRepo.DeploymentService.AbortCanary(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Aborted Canary of Deployment: %s", deploymentName)})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetBlueGreen(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetBlueGreen(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) SwitchBackBlueGreen(ctx *gin.Context, namespace, deploymentName string) {
+	_, err := dao.ServiceRepo.DeploymentService.SwitchBackBlueGreen(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Switched Back Deployment: %s", deploymentName)})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) CreateSchedule(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.ScheduleReq) {
+	response, err := dao.ServiceRepo.DeploymentService.CreateSchedule(namespace, deploymentName, *payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetSchedules(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetSchedules(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) UpdateSchedule(ctx *gin.Context, namespace, deploymentName, scheduleID string, payload *model_deployment.ScheduleReq) {
+	response, err := dao.ServiceRepo.DeploymentService.UpdateSchedule(namespace, deploymentName, scheduleID, *payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) DeleteSchedule(ctx *gin.Context, namespace, deploymentName, scheduleID string) {
+	err := dao.ServiceRepo.DeploymentService.DeleteSchedule(namespace, deploymentName, scheduleID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": fmt.Sprintf("Successfully Deleted Schedule %s of Deployment: %s", scheduleID, deploymentName)})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetScheduleNextRuns(ctx *gin.Context, namespace, deploymentName, scheduleID string, count int) {
+	response, err := dao.ServiceRepo.DeploymentService.GetScheduleNextRuns(namespace, deploymentName, scheduleID, count)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetScheduleRuns(ctx *gin.Context, namespace, deploymentName, scheduleID string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetScheduleRuns(namespace, deploymentName, scheduleID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) PauseDeployment(ctx *gin.Context, namespace, deploymentName string) {
+	resp, err := dao.ServiceRepo.DeploymentService.PauseDeployment(namespace, deploymentName, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Paused Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) ResumeDeployment(ctx *gin.Context, namespace, deploymentName string) {
+	resp, err := dao.ServiceRepo.DeploymentService.ResumeDeployment(namespace, deploymentName, requestActor(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Resumed Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetDrift(ctx *gin.Context, namespace string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetDrift(namespace)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) ReconcileNamespace(ctx *gin.Context, namespace string) {
+	response, err := dao.ServiceRepo.DeploymentService.ReconcileNamespace(namespace)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetDeploymentOperations(ctx *gin.Context, namespace, deploymentName string) {
+	response, err := dao.ServiceRepo.DeploymentService.GetDeploymentOperations(namespace, deploymentName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) GetDeploymentHistory(ctx *gin.Context, namespace, deploymentName string, query *model_deployment.HistoryQuery) {
+	response, err := dao.ServiceRepo.DeploymentService.GetDeploymentHistory(namespace, deploymentName, *query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao DeploymentDao) ApplyDeploymentSpec(ctx *gin.Context, namespace, deploymentName string, payload *model_deployment.DeploymentSpecReq) {
+	resp, err := dao.ServiceRepo.DeploymentService.ApplyDeploymentSpec(namespace, deploymentName, payload, requestActor(ctx))
+	var validationErr *svc.ValidationError
+	if errors.As(err, &validationErr) {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"message": validationErr.Error()})
+		ctx.Abort()
+		return
+	}
+	// Fields another field manager owns are reported, not taken over
+	if apierrors.IsConflict(err) {
+		ctx.JSON(http.StatusConflict, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Successfully Applied Spec of Deployment: %s", deploymentName),
+		"result":  resp})
+	ctx.Abort()
+}
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"deployment-service/apps/repository/adapter"
+	"deployment-service/apps/svc"
+	"net/http"
+
+	model_tenant "deployment-service/models/model.tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenantDao struct {
+	ServiceRepo *svc.ServiceRepository
+}
+
+type ITenantDao interface {
+	UpdateTenantQuota(ctx *gin.Context, namespace string, payload model_tenant.UpdateTenantQuotaReq)
+	UpdateReconcilePolicy(ctx *gin.Context, namespace string, payload model_tenant.UpdateReconcilePolicyReq)
+}
+
+func NewTenantDao(repository *adapter.Repository) ITenantDao {
+	return &TenantDao{
+		ServiceRepo: svc.NewServiceRepo(repository),
+	}
+}
+
+func (dao TenantDao) UpdateTenantQuota(ctx *gin.Context, namespace string, payload model_tenant.UpdateTenantQuotaReq) {
+	response, err := dao.ServiceRepo.TenantService.UpdateTenantQuota(namespace, payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
+
+func (dao TenantDao) UpdateReconcilePolicy(ctx *gin.Context, namespace string, payload model_tenant.UpdateReconcilePolicyReq) {
+	response, err := dao.ServiceRepo.TenantService.UpdateReconcilePolicy(namespace, payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"message": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"message": response})
+	ctx.Abort()
+}
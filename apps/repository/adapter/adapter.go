@@ -1,6 +1,8 @@
 package adapter
 
 import (
+	"sync/atomic"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 	"k8s.io/client-go/kubernetes"
@@ -13,6 +15,7 @@ type Repository struct {
 	// PSql    *PSql
 	MongoDB    *MongoDB
 	Kubernetes *Kubernetes
+	Metrics    MetricsProvider
 }
 
 type RedDB struct {
@@ -29,6 +32,8 @@ type MongoDB struct {
 
 type Kubernetes struct {
 	connection *kubernetes.Clientset
+	// cache is set once the informers have synced, reads go to the api server while it is nil
+	cache atomic.Pointer[KubernetesCache]
 }
 
 type IKubernetesAdapter interface {
@@ -57,11 +62,12 @@ type IPSqlQueryAdapter interface {
 	Exec(queryString string)
 }
 
-func RepositoryAdapter(mongoClient *mongo.Client, kubernetesClient *kubernetes.Clientset) *Repository {
+func RepositoryAdapter(mongoClient *mongo.Client, kubernetesClient *kubernetes.Clientset, metrics MetricsProvider) *Repository {
 	return &Repository{
 		// &RedDB{connection: redis},
 		// &PSql{connection: psqlClient},
 		&MongoDB{connection: mongoClient},
 		&Kubernetes{connection: kubernetesClient},
+		metrics,
 	}
 }
@@ -0,0 +1,313 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// SpecFieldManager owns the fields of the deployment specs applied declaratively
+	SpecFieldManager = "deployment-service-spec"
+)
+
+// FieldChange is a field of a deployment that an apply changed, a nil value is a field that wasn't set
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// SpecPodSpec is the part of the pod template a deployment spec declares: the main container named after the
+// deployment with its image, resources, env and probes, the sidecars, the init containers and the pull secrets
+func SpecPodSpec(deploymentName, image string, options DeploymentOptions) corev1.PodSpec {
+	main := corev1.Container{
+		Name:           deploymentName,
+		Image:          image,
+		Resources:      options.Resources,
+		Env:            options.Env,
+		LivenessProbe:  options.Liveness,
+		ReadinessProbe: options.Readiness,
+		StartupProbe:   options.Startup,
+	}
+	return corev1.PodSpec{
+		ImagePullSecrets: LocalObjectReferences(options.ImagePullSecrets),
+		Containers:       append([]corev1.Container{main}, options.Sidecars...),
+		InitContainers:   options.InitContainers,
+	}
+}
+
+// serviceFieldManager is the field manager the api server records for the service's own creates and updates,
+// client-go names it after the binary through the default user agent
+var serviceFieldManager = strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0]
+
+var conflictManager = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// ownConflict reports whether all fields of an apply conflict are owned by the service itself, e.g. the image
+// set by the create. Those are taken over, fields of any other manager are left to the caller to resolve.
+func ownConflict(err error) bool {
+	status, ok := err.(errors.APIStatus)
+	if !ok || !errors.IsConflict(err) || status.Status().Details == nil || len(status.Status().Details.Causes) == 0 {
+		return false
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		match := conflictManager.FindStringSubmatch(cause.Message)
+		if match == nil || (match[1] != serviceFieldManager && match[1] != SpecFieldManager) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDeployment server-side applies the replicas and pod spec of a deployment under SpecFieldManager.
+// A nil replicas leaves the replica count to its current owner, e.g. an autoscaler. Without force, fields
+// another manager owns with a different value fail the apply with a conflict naming them.
+func (k *Kubernetes) applyDeployment(namespace, deploymentName string, replicas *int32, podSpec corev1.PodSpec, force bool) (*appsv1.Deployment, error) {
+	spec := map[string]interface{}{
+		"template": map[string]interface{}{"spec": podSpec},
+	}
+	if replicas != nil {
+		spec["replicas"] = *replicas
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": deploymentName, "namespace": namespace},
+		"spec":       spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return k.connection.AppsV1().Deployments(namespace).Patch(context.TODO(), deploymentName, types.ApplyPatchType, patch,
+		metav1.PatchOptions{FieldManager: SpecFieldManager, Force: ptr.To(force)})
+}
+
+// prunePatch is the strategic merge patch that only deletes the entries podSpec leaves out from a deployment,
+// nil when there are none. It carries the resource version it was computed from, a write since then is a conflict.
+func prunePatch(deployment *appsv1.Deployment, podSpec corev1.PodSpec) ([]byte, error) {
+	pruned := deployment.DeepCopy()
+	if !prunePodSpec(pruned, podSpec) {
+		return nil, nil
+	}
+	original, err := json.Marshal(deployment)
+	if err != nil {
+		return nil, err
+	}
+	modified, err := json.Marshal(pruned)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(original, modified, appsv1.Deployment{})
+	if err != nil {
+		return nil, err
+	}
+	var patchMap map[string]interface{}
+	if err := json.Unmarshal(patch, &patchMap); err != nil {
+		return nil, err
+	}
+	patchMap["metadata"] = map[string]interface{}{"resourceVersion": deployment.ResourceVersion}
+	return json.Marshal(patchMap)
+}
+
+// pruneDeployment removes the entries podSpec leaves out from a deployment
+func (k *Kubernetes) pruneDeployment(deployment *appsv1.Deployment, podSpec corev1.PodSpec) (*appsv1.Deployment, error) {
+	patch, err := prunePatch(deployment, podSpec)
+	if err != nil || patch == nil {
+		return deployment, err
+	}
+	return k.connection.AppsV1().Deployments(deployment.Namespace).Patch(context.TODO(), deployment.Name, types.StrategicMergePatchType, patch,
+		metav1.PatchOptions{FieldManager: SpecFieldManager})
+}
+
+// pruneContainers removes the containers, env vars, resources, probes, commands and args the desired containers leave out
+func pruneContainers(containers []corev1.Container, desired []corev1.Container) ([]corev1.Container, bool) {
+	changed := false
+	var pruned []corev1.Container
+	for _, container := range containers {
+		i := slices.IndexFunc(desired, func(d corev1.Container) bool { return d.Name == container.Name })
+		if i < 0 {
+			changed = true
+			continue
+		}
+		want := desired[i]
+		env := slices.DeleteFunc(slices.Clone(container.Env), func(e corev1.EnvVar) bool {
+			return !slices.ContainsFunc(want.Env, func(w corev1.EnvVar) bool { return w.Name == e.Name })
+		})
+		if len(env) != len(container.Env) {
+			container.Env, changed = env, true
+		}
+		for _, list := range []struct{ have, want corev1.ResourceList }{
+			{container.Resources.Requests, want.Resources.Requests},
+			{container.Resources.Limits, want.Resources.Limits},
+		} {
+			for name := range list.have {
+				if _, ok := list.want[name]; !ok {
+					delete(list.have, name)
+					changed = true
+				}
+			}
+		}
+		for _, probe := range []struct {
+			have **corev1.Probe
+			want *corev1.Probe
+		}{
+			{&container.LivenessProbe, want.LivenessProbe},
+			{&container.ReadinessProbe, want.ReadinessProbe},
+			{&container.StartupProbe, want.StartupProbe},
+		} {
+			if probe.want == nil && *probe.have != nil {
+				*probe.have, changed = nil, true
+			}
+		}
+		if want.Command == nil && container.Command != nil {
+			container.Command, changed = nil, true
+		}
+		if want.Args == nil && container.Args != nil {
+			container.Args, changed = nil, true
+		}
+		pruned = append(pruned, container)
+	}
+	return pruned, changed
+}
+
+// prunePodSpec removes what podSpec leaves out from a deployment's pod template. Server-side apply only removes
+// the fields SpecFieldManager owns alone, entries set by the create or an update are pruned here.
+func prunePodSpec(deployment *appsv1.Deployment, podSpec corev1.PodSpec) bool {
+	template := &deployment.Spec.Template.Spec
+	var containersChanged, initChanged bool
+	template.Containers, containersChanged = pruneContainers(template.Containers, podSpec.Containers)
+	template.InitContainers, initChanged = pruneContainers(template.InitContainers, podSpec.InitContainers)
+	pullSecrets := slices.DeleteFunc(slices.Clone(template.ImagePullSecrets), func(ref corev1.LocalObjectReference) bool {
+		return !slices.Contains(podSpec.ImagePullSecrets, ref)
+	})
+	pullSecretsChanged := len(pullSecrets) != len(template.ImagePullSecrets)
+	template.ImagePullSecrets = pullSecrets
+	return containersChanged || initChanged || pullSecretsChanged
+}
+
+// ApplyDeploymentSpec makes a deployment run exactly the given replicas and pod spec. The spec is server-side
+// applied under SpecFieldManager, which removes what the previous apply declared and this one leaves out.
+// Entries the create or an update set are pruned by a second, targeted patch. Fields the service's own writes
+// set are taken over, a field another manager owns with a different value fails the apply with a conflict.
+// It returns the deployment before and after the apply.
+func (k *Kubernetes) ApplyDeploymentSpec(namespace, deploymentName string, replicas *int32, podSpec corev1.PodSpec) (*appsv1.Deployment, *appsv1.Deployment, error) {
+	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
+	live, err := deploymentsClient.Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	applied, err := k.applyDeployment(namespace, deploymentName, replicas, podSpec, false)
+	if ownConflict(err) {
+		applied, err = k.applyDeployment(namespace, deploymentName, replicas, podSpec, true)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to apply spec of deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pruned, err := k.pruneDeployment(applied, podSpec)
+		if errors.IsConflict(err) {
+			if latest, getErr := deploymentsClient.Get(context.TODO(), deploymentName, metav1.GetOptions{}); getErr == nil {
+				applied = latest
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		applied = pruned
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to prune spec of deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if applied.ResourceVersion == live.ResourceVersion {
+		return live, live, nil
+	}
+	k.awaitCached(applied)
+
+	fmt.Printf("Successfully applied spec of deployment %s in namespace %s\n", deploymentName, namespace)
+	return live, applied, nil
+}
+
+// diffContainers lists the declared fields that differ between two versions of a list of containers
+func diffContainers(prefix string, before, after []corev1.Container) []FieldChange {
+	var changes []FieldChange
+	find := func(containers []corev1.Container, name string) *corev1.Container {
+		if i := slices.IndexFunc(containers, func(c corev1.Container) bool { return c.Name == name }); i >= 0 {
+			return &containers[i]
+		}
+		return nil
+	}
+	var names []string
+	for _, container := range append(slices.Clone(before), after...) {
+		if !slices.Contains(names, container.Name) {
+			names = append(names, container.Name)
+		}
+	}
+	for _, name := range names {
+		field := prefix + "." + name
+		old, updated := find(before, name), find(after, name)
+		if old == nil || updated == nil {
+			var beforeImage, afterImage interface{}
+			if old != nil {
+				beforeImage = old.Image
+			}
+			if updated != nil {
+				afterImage = updated.Image
+			}
+			changes = append(changes, FieldChange{Field: field, Before: beforeImage, After: afterImage})
+			continue
+		}
+		for _, f := range []struct {
+			name          string
+			before, after interface{}
+		}{
+			{"image", old.Image, updated.Image},
+			{"command", old.Command, updated.Command},
+			{"args", old.Args, updated.Args},
+			{"resources", old.Resources, updated.Resources},
+			{"env", old.Env, updated.Env},
+			{"liveness_probe", old.LivenessProbe, updated.LivenessProbe},
+			{"readiness_probe", old.ReadinessProbe, updated.ReadinessProbe},
+			{"startup_probe", old.StartupProbe, updated.StartupProbe},
+		} {
+			if !equality.Semantic.DeepEqual(f.before, f.after) {
+				changes = append(changes, FieldChange{Field: field + "." + f.name, Before: f.before, After: f.after})
+			}
+		}
+	}
+	return changes
+}
+
+// DiffDeploymentSpecs lists the fields a deployment spec declares that differ between two versions of a deployment
+func DiffDeploymentSpecs(before, after *appsv1.Deployment) []FieldChange {
+	changes := []FieldChange{}
+	if !equality.Semantic.DeepEqual(before.Spec.Replicas, after.Spec.Replicas) {
+		changes = append(changes, FieldChange{Field: "replicas", Before: before.Spec.Replicas, After: after.Spec.Replicas})
+	}
+	beforeSecrets := LocalObjectNames(before.Spec.Template.Spec.ImagePullSecrets)
+	afterSecrets := LocalObjectNames(after.Spec.Template.Spec.ImagePullSecrets)
+	if !slices.Equal(beforeSecrets, afterSecrets) {
+		changes = append(changes, FieldChange{Field: "image_pull_secrets", Before: beforeSecrets, After: afterSecrets})
+	}
+	changes = append(changes, diffContainers("containers", before.Spec.Template.Spec.Containers, after.Spec.Template.Spec.Containers)...)
+	changes = append(changes, diffContainers("init_containers", before.Spec.Template.Spec.InitContainers, after.Spec.Template.Spec.InitContainers)...)
+	return changes
+}
@@ -0,0 +1,288 @@
+package adapter
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/utils/ptr"
+)
+
+func specDeployment(replicas int32, podSpec corev1.PodSpec) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{}
+	deployment.Spec.Replicas = ptr.To(replicas)
+	deployment.Spec.Template.Spec = *podSpec.DeepCopy()
+	return deployment
+}
+
+func TestPruneContainers(t *testing.T) {
+	live := []corev1.Container{
+		{
+			Name:    "web",
+			Image:   "nginx:1",
+			Command: []string{"nginx"},
+			Args:    []string{"-g", "daemon off;"},
+			Env:     []corev1.EnvVar{{Name: "KEEP", Value: "1"}, {Name: "DROP", Value: "2"}},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+			},
+			LivenessProbe:  &corev1.Probe{PeriodSeconds: 10},
+			ReadinessProbe: &corev1.Probe{PeriodSeconds: 5},
+		},
+		{Name: "sidecar", Image: "envoy:1"},
+	}
+	desired := []corev1.Container{
+		{
+			Name:  "web",
+			Image: "nginx:1",
+			Env:   []corev1.EnvVar{{Name: "KEEP", Value: "1"}},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			},
+			ReadinessProbe: &corev1.Probe{PeriodSeconds: 5},
+		},
+	}
+
+	pruned, changed := pruneContainers(live, desired)
+	if !changed {
+		t.Fatal("expected the containers to change")
+	}
+	if len(pruned) != 1 || pruned[0].Name != "web" {
+		t.Fatalf("expected only the web container to be kept, got %+v", pruned)
+	}
+	web := pruned[0]
+	if len(web.Env) != 1 || web.Env[0].Name != "KEEP" {
+		t.Errorf("expected only the KEEP env var, got %+v", web.Env)
+	}
+	if _, ok := web.Resources.Requests[corev1.ResourceMemory]; ok {
+		t.Error("expected the memory request to be pruned")
+	}
+	if _, ok := web.Resources.Requests[corev1.ResourceCPU]; !ok {
+		t.Error("expected the cpu request to be kept")
+	}
+	if len(web.Resources.Limits) != 0 {
+		t.Errorf("expected the limits to be pruned, got %+v", web.Resources.Limits)
+	}
+	if web.LivenessProbe != nil {
+		t.Error("expected the liveness probe to be pruned")
+	}
+	if web.ReadinessProbe == nil {
+		t.Error("expected the readiness probe to be kept")
+	}
+	if web.Command != nil || web.Args != nil {
+		t.Errorf("expected the command and args to be pruned, got %v %v", web.Command, web.Args)
+	}
+}
+
+func TestPruneContainersUnchanged(t *testing.T) {
+	containers := []corev1.Container{{
+		Name:  "web",
+		Image: "nginx:1",
+		Env:   []corev1.EnvVar{{Name: "KEEP", Value: "1"}},
+	}}
+	pruned, changed := pruneContainers(containers, containers)
+	if changed {
+		t.Error("expected containers matching the desired ones to be unchanged")
+	}
+	if !equality.Semantic.DeepEqual(pruned, containers) {
+		t.Errorf("expected %+v, got %+v", containers, pruned)
+	}
+}
+
+func TestPrunePodSpec(t *testing.T) {
+	podSpec := SpecPodSpec("web", "nginx:1", DeploymentOptions{ImagePullSecrets: []string{"registry"}})
+	deployment := specDeployment(1, SpecPodSpec("web", "nginx:1", DeploymentOptions{
+		ImagePullSecrets: []string{"registry", "old-registry"},
+		Sidecars:         []corev1.Container{{Name: "sidecar", Image: "envoy:1"}},
+		InitContainers:   []corev1.Container{{Name: "migrate", Image: "migrate:1"}},
+	}))
+
+	if !prunePodSpec(deployment, podSpec) {
+		t.Fatal("expected the pod spec to change")
+	}
+	template := deployment.Spec.Template.Spec
+	if names := LocalObjectNames(template.ImagePullSecrets); len(names) != 1 || names[0] != "registry" {
+		t.Errorf("expected only the registry pull secret, got %v", names)
+	}
+	if len(template.Containers) != 1 || template.Containers[0].Name != "web" {
+		t.Errorf("expected only the web container, got %+v", template.Containers)
+	}
+	if len(template.InitContainers) != 0 {
+		t.Errorf("expected the init containers to be pruned, got %+v", template.InitContainers)
+	}
+}
+
+// Applying a spec projects it onto the live deployment and prunes what it leaves out. Projecting the same
+// spec onto the result must change nothing, so applying it a second time doesn't write.
+func TestApplySameSpecTwice(t *testing.T) {
+	podSpec := SpecPodSpec("web", "nginx:2", DeploymentOptions{
+		Env:      []corev1.EnvVar{{Name: "KEEP", Value: "1"}},
+		Sidecars: []corev1.Container{{Name: "sidecar", Image: "envoy:1"}},
+	})
+	live := specDeployment(2, SpecPodSpec("web", "nginx:1", DeploymentOptions{
+		Env: []corev1.EnvVar{{Name: "KEEP", Value: "1"}, {Name: "DROP", Value: "2"}},
+	}))
+
+	project := func(live *appsv1.Deployment) *appsv1.Deployment {
+		projected := live.DeepCopy()
+		projected.Spec.Replicas = ptr.To(int32(3))
+		for _, container := range podSpec.Containers {
+			template := &projected.Spec.Template.Spec
+			if i := slices.IndexFunc(template.Containers, func(c corev1.Container) bool { return c.Name == container.Name }); i >= 0 {
+				template.Containers[i].Image = container.Image
+			} else {
+				template.Containers = append(template.Containers, container)
+			}
+		}
+		prunePodSpec(projected, podSpec)
+		return projected
+	}
+
+	first := project(live)
+	if equality.Semantic.DeepEqual(live.Spec, first.Spec) {
+		t.Fatal("expected the first apply to change the deployment")
+	}
+	if changes := DiffDeploymentSpecs(live, first); len(changes) == 0 {
+		t.Fatal("expected the first apply to report changes")
+	}
+	second := project(first)
+	if !equality.Semantic.DeepEqual(first.Spec, second.Spec) {
+		t.Errorf("expected the second apply to be a no-op, got %+v", DiffDeploymentSpecs(first, second))
+	}
+	if changes := DiffDeploymentSpecs(first, second); len(changes) != 0 {
+		t.Errorf("expected no changes from the second apply, got %+v", changes)
+	}
+}
+
+func TestDiffDeploymentSpecs(t *testing.T) {
+	before := specDeployment(1, SpecPodSpec("web", "nginx:1", DeploymentOptions{
+		ImagePullSecrets: []string{"registry"},
+		InitContainers:   []corev1.Container{{Name: "migrate", Image: "migrate:1"}},
+	}))
+	after := specDeployment(2, SpecPodSpec("web", "nginx:2", DeploymentOptions{
+		Env:      []corev1.EnvVar{{Name: "MODE", Value: "prod"}},
+		Sidecars: []corev1.Container{{Name: "sidecar", Image: "envoy:1"}},
+	}))
+
+	changes := DiffDeploymentSpecs(before, after)
+	fields := map[string]FieldChange{}
+	for _, change := range changes {
+		fields[change.Field] = change
+	}
+	for _, field := range []string{
+		"replicas",
+		"image_pull_secrets",
+		"containers.web.image",
+		"containers.web.env",
+		"containers.sidecar",
+		"init_containers.migrate",
+	} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("expected a change of %s, got %+v", field, changes)
+		}
+	}
+	if len(changes) != 6 {
+		t.Errorf("expected 6 changes, got %+v", changes)
+	}
+	if change := fields["containers.sidecar"]; change.Before != nil || change.After != "envoy:1" {
+		t.Errorf("expected the added sidecar to go from nil to its image, got %+v", change)
+	}
+	if change := fields["init_containers.migrate"]; change.Before != "migrate:1" || change.After != nil {
+		t.Errorf("expected the removed init container to go from its image to nil, got %+v", change)
+	}
+	if changes := DiffDeploymentSpecs(before, before.DeepCopy()); len(changes) != 0 {
+		t.Errorf("expected no changes between equal deployments, got %+v", changes)
+	}
+}
+
+func TestPrunePatch(t *testing.T) {
+	podSpec := SpecPodSpec("web", "nginx:1", DeploymentOptions{
+		Env:              []corev1.EnvVar{{Name: "KEEP", Value: "1"}},
+		ImagePullSecrets: []string{"registry"},
+	})
+	deployment := specDeployment(1, SpecPodSpec("web", "nginx:1", DeploymentOptions{
+		Env:              []corev1.EnvVar{{Name: "KEEP", Value: "1"}, {Name: "DROP", Value: "2"}},
+		Liveness:         &corev1.Probe{PeriodSeconds: 10},
+		ImagePullSecrets: []string{"registry", "old-registry"},
+		Sidecars:         []corev1.Container{{Name: "sidecar", Image: "envoy:1"}},
+	}))
+	deployment.Name, deployment.Namespace, deployment.ResourceVersion = "web", "tenant", "42"
+
+	patch, err := prunePatch(deployment, podSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var patchMap map[string]interface{}
+	if err := json.Unmarshal(patch, &patchMap); err != nil {
+		t.Fatal(err)
+	}
+	if version := patchMap["metadata"].(map[string]interface{})["resourceVersion"]; version != "42" {
+		t.Errorf("expected the patch to carry resource version 42, got %v", version)
+	}
+
+	original, err := json.Marshal(deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, appsv1.Deployment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result appsv1.Deployment
+	if err := json.Unmarshal(patched, &result); err != nil {
+		t.Fatal(err)
+	}
+	expected := deployment.DeepCopy()
+	prunePodSpec(expected, podSpec)
+	if !equality.Semantic.DeepEqual(result.Spec, expected.Spec) {
+		t.Errorf("expected the patched deployment to be pruned, got %+v", DiffDeploymentSpecs(expected, &result))
+	}
+	if patch, err := prunePatch(&result, podSpec); err != nil || patch != nil {
+		t.Errorf("expected nothing left to prune, got %s %v", patch, err)
+	}
+}
+
+func applyConflict(managers ...string) error {
+	var causes []metav1.StatusCause
+	for _, manager := range managers {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "` + manager + `" using apps/v1`,
+			Field:   ".spec.template.spec.containers[name=\"web\"].image",
+		})
+	}
+	err := errors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web", nil)
+	err.ErrStatus.Details.Causes = causes
+	return err
+}
+
+func TestOwnConflict(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"no error", nil, false},
+		{"not a conflict", errors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web"), false},
+		{"service's own update", applyConflict(serviceFieldManager), true},
+		{"previous spec apply", applyConflict(SpecFieldManager, serviceFieldManager), true},
+		{"another manager", applyConflict("kubectl-edit"), false},
+		{"own and another manager", applyConflict(serviceFieldManager, "kubectl-edit"), false},
+		{"conflict without causes", applyConflict(), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ownConflict(tc.err); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyHorizontalPodAutoscaler creates or updates an autoscaling/v2 HPA targeting a deployment.
+// A nil utilization target leaves that resource out of the metrics.
+func (k *Kubernetes) ApplyHorizontalPodAutoscaler(namespace, hpaName, deploymentName string, minReplicas, maxReplicas int32,
+	targetCPUUtilization, targetMemoryUtilization *int32) error {
+	hpaClient := k.connection.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+
+	var metrics []autoscalingv2.MetricSpec
+	targets := []struct {
+		name        corev1.ResourceName
+		utilization *int32
+	}{
+		{corev1.ResourceCPU, targetCPUUtilization},
+		{corev1.ResourceMemory, targetMemoryUtilization},
+	}
+	for _, target := range targets {
+		if target.utilization == nil {
+			continue
+		}
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: target.name,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: target.utilization,
+				},
+			},
+		})
+	}
+	spec := autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       deploymentName,
+		},
+		MinReplicas: &minReplicas,
+		MaxReplicas: maxReplicas,
+		Metrics:     metrics,
+	}
+
+	existing, err := hpaClient.Get(context.TODO(), hpaName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      hpaName,
+				Namespace: namespace,
+				Labels: map[string]string{
+					"app":          deploymentName,
+					ManagedByLabel: ManagedByValue,
+				},
+			},
+			Spec: spec,
+		}
+		if _, err = hpaClient.Create(context.TODO(), hpa, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create autoscaler %s in namespace %s: %w", hpaName, namespace, err)
+		}
+		fmt.Printf("Successfully created autoscaler %s in namespace %s\n", hpaName, namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get autoscaler %s in namespace %s: %w", hpaName, namespace, err)
+	}
+
+	existing.Spec = spec
+	if _, err = hpaClient.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update autoscaler %s in namespace %s: %w", hpaName, namespace, err)
+	}
+	fmt.Printf("Successfully updated autoscaler %s in namespace %s\n", hpaName, namespace)
+	return nil
+}
+
+// GetHorizontalPodAutoscaler fetches an HPA by name
+func (k *Kubernetes) GetHorizontalPodAutoscaler(namespace, hpaName string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpa, err := k.connection.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(context.TODO(), hpaName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get autoscaler %s in namespace %s: %w", hpaName, namespace, err)
+	}
+	return hpa, nil
+}
+
+// DeleteHorizontalPodAutoscaler deletes an HPA in the specified namespace.
+func (k *Kubernetes) DeleteHorizontalPodAutoscaler(namespace, hpaName string) error {
+	err := k.connection.AutoscalingV2().HorizontalPodAutoscalers(namespace).Delete(context.TODO(), hpaName, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			fmt.Printf("Autoscaler %s does not exist in namespace %s\n", hpaName, namespace)
+			return nil
+		}
+		return fmt.Errorf("failed to delete autoscaler %s in namespace %s: %w", hpaName, namespace, err)
+	}
+
+	fmt.Printf("Successfully deleted autoscaler %s in namespace %s\n", hpaName, namespace)
+	return nil
+}
@@ -0,0 +1,181 @@
+package adapter
+
+import (
+	"context"
+	"deployment-service/constants"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// KubernetesCache holds the shared informers that answer the adapter's deployment, service, pod and event reads.
+// The informers keep a local copy of the cluster up to date through watches, so list views don't cost
+// a round-trip to the api server per deployment. Deployments and services are limited to the ones this
+// service manages, lists of everything in a namespace keep going to the api server.
+// Pods and events aren't cached, watching them would take a watch per tenant namespace for as long as it exists.
+type KubernetesCache struct {
+	deployments appsinformers.DeploymentInformer
+	services    coreinformers.ServiceInformer
+}
+
+// StartCache starts the informers and serves reads from them once they have synced, until ctx is done.
+// Reads keep going to the api server until then, or for good when the informers fail to sync.
+func (k *Kubernetes) StartCache(ctx context.Context) error {
+	cacheCtx, stop := context.WithCancel(ctx)
+	resync := time.Duration(constants.KUBERNETES_CACHE_RESYNC_SECONDS) * time.Second
+	factory := informers.NewSharedInformerFactoryWithOptions(k.connection, resync,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = managedBySelector
+		}))
+	cache := &KubernetesCache{
+		deployments: factory.Apps().V1().Deployments(),
+		services:    factory.Core().V1().Services(),
+	}
+	// The factory only starts the informers requested before Start
+	cache.deployments.Informer()
+	cache.services.Informer()
+	factory.Start(cacheCtx.Done())
+
+	syncCtx, cancel := context.WithTimeout(cacheCtx, time.Duration(constants.KUBERNETES_CACHE_SYNC_TIMEOUT_SECONDS)*time.Second)
+	defer cancel()
+	for informerType, synced := range factory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			stop()
+			factory.Shutdown()
+			return fmt.Errorf("failed to sync kubernetes cache for %v", informerType)
+		}
+	}
+	k.cache.Store(cache)
+	fmt.Println("Kubernetes cache synced")
+
+	go func() {
+		<-cacheCtx.Done()
+		k.cache.Store(nil)
+		stop()
+		factory.Shutdown()
+	}()
+	return nil
+}
+
+// AdoptDeployment labels a deployment and its service as managed by this service, objects created
+// before the label was introduced are otherwise invisible to the cache. Missing objects are skipped.
+func (k *Kubernetes) AdoptDeployment(namespace, deploymentName, serviceName string) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:%q}}}`, ManagedByLabel, ManagedByValue))
+	deployment, err := k.connection.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if err == nil && deployment.Labels[ManagedByLabel] != ManagedByValue {
+		_, err := k.connection.AppsV1().Deployments(namespace).Patch(context.TODO(), deploymentName, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to label deployment %s in namespace %s: %w", deploymentName, namespace, err)
+		}
+	}
+	service, err := k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get service %s in namespace %s: %w", serviceName, namespace, err)
+	}
+	if err == nil && service.Labels[ManagedByLabel] != ManagedByValue {
+		_, err := k.connection.CoreV1().Services(namespace).Patch(context.TODO(), serviceName, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to label service %s in namespace %s: %w", serviceName, namespace, err)
+		}
+	}
+	return nil
+}
+
+// cachedObject is a pointer to a Kubernetes object the cache holds
+type cachedObject[T any] interface {
+	*T
+	metav1.Object
+	DeepCopy() *T
+}
+
+// cachedItems copies objects out of the cache, which must not be mutated, ordered by name like a list call
+func cachedItems[T any, P cachedObject[T]](objects []P) []T {
+	sort.Slice(objects, func(i, j int) bool { return objects[i].GetName() < objects[j].GetName() })
+	items := make([]T, 0, len(objects))
+	for _, object := range objects {
+		items = append(items, *object.DeepCopy())
+	}
+	return items
+}
+
+// getDeployment reads a deployment from the cache, or from the api server when the cache hasn't
+// synced or hasn't seen the deployment yet
+func (k *Kubernetes) getDeployment(namespace, deploymentName string) (*appsv1.Deployment, error) {
+	if cache := k.cache.Load(); cache != nil {
+		if deployment, err := cache.deployments.Lister().Deployments(namespace).Get(deploymentName); err == nil {
+			return deployment.DeepCopy(), nil
+		}
+	}
+	return k.connection.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
+}
+
+// getService reads a service from the cache, or from the api server when the cache hasn't
+// synced or hasn't seen the service yet
+func (k *Kubernetes) getService(namespace, serviceName string) (*corev1.Service, error) {
+	if cache := k.cache.Load(); cache != nil {
+		if service, err := cache.services.Lister().Services(namespace).Get(serviceName); err == nil {
+			return service.DeepCopy(), nil
+		}
+	}
+	return k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+}
+
+// awaitCached waits until the cache holds the deployment or service the adapter just created or updated,
+// so a read right after the write doesn't return the version the watch hasn't replaced yet.
+// The cache is only ever filled by the watch; objects this service doesn't manage aren't cached and aren't waited for.
+func (k *Kubernetes) awaitCached(object metav1.Object) {
+	k.awaitCache(object, func(cached metav1.Object) bool {
+		return cached != nil && (cached.GetUID() != object.GetUID() || cached.GetResourceVersion() == object.GetResourceVersion())
+	})
+}
+
+// awaitUncached waits until the watch has removed a deployment or service the adapter just deleted from the cache
+func (k *Kubernetes) awaitUncached(object metav1.Object) {
+	k.awaitCache(object, func(cached metav1.Object) bool {
+		return cached == nil || cached.GetUID() != object.GetUID()
+	})
+}
+
+// awaitCache polls the cached version of an object until done accepts it, a nil version is an object the
+// cache doesn't hold. It gives up after KUBERNETES_CACHE_WAIT_SECONDS, e.g. when a newer write overtook this one.
+func (k *Kubernetes) awaitCache(object metav1.Object, done func(cached metav1.Object) bool) {
+	cache := k.cache.Load()
+	if cache == nil || object.GetLabels()[ManagedByLabel] != ManagedByValue {
+		return
+	}
+	var store toolscache.Store
+	switch object.(type) {
+	case *appsv1.Deployment:
+		store = cache.deployments.Informer().GetStore()
+	case *corev1.Service:
+		store = cache.services.Informer().GetStore()
+	default:
+		return
+	}
+	key, err := toolscache.MetaNamespaceKeyFunc(object)
+	if err != nil {
+		return
+	}
+	timeout := time.Duration(constants.KUBERNETES_CACHE_WAIT_SECONDS) * time.Second
+	_ = wait.PollUntilContextTimeout(context.TODO(), 50*time.Millisecond, timeout, true, func(context.Context) (bool, error) {
+		item, exists, err := store.GetByKey(key)
+		if err != nil || !exists {
+			return done(nil), nil
+		}
+		return done(item.(metav1.Object)), nil
+	})
+}
@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CloneDeployment creates a deployment running the pod template of an existing one with another main container image.
+// The clone's pods keep the source labels, so the source's service also selects them, and carry extraLabels
+// which the clone's selector adds to tell them apart.
+func (k *Kubernetes) CloneDeployment(namespace, sourceName, cloneName string, extraLabels map[string]string, replicas int32, image string) error {
+	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
+	source, err := deploymentsClient.Get(context.TODO(), sourceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment %s in namespace %s: %w", sourceName, namespace, err)
+	}
+	if _, err := deploymentsClient.Get(context.TODO(), cloneName, metav1.GetOptions{}); err == nil {
+		return errors.NewAlreadyExists(schema.GroupResource{Group: "apps", Resource: "deployments"}, cloneName)
+	}
+
+	labels := map[string]string{ManagedByLabel: ManagedByValue}
+	selector := map[string]string{}
+	for key, value := range source.Spec.Selector.MatchLabels {
+		selector[key] = value
+		labels[key] = value
+	}
+	for key, value := range extraLabels {
+		selector[key] = value
+		labels[key] = value
+	}
+
+	template := source.Spec.Template.DeepCopy()
+	for key, value := range extraLabels {
+		template.Labels[key] = value
+	}
+	MainContainer(&template.Spec, sourceName).Image = image
+
+	clone := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cloneName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Strategy: source.Spec.Strategy,
+			Template: *template,
+		},
+	}
+	created, err := deploymentsClient.Create(context.TODO(), clone, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create deployment %s in namespace %s: %w", cloneName, namespace, err)
+	}
+	k.awaitCached(created)
+
+	fmt.Printf("Successfully created deployment %s from %s in namespace %s\n", cloneName, sourceName, namespace)
+	return nil
+}
@@ -0,0 +1,172 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ManagedByLabel marks the objects created and owned by this service in a tenant namespace
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedByValue = "deployment-service"
+)
+
+var managedBySelector = fmt.Sprintf("%s=%s", ManagedByLabel, ManagedByValue)
+
+// ApplySecret creates or replaces an Opaque secret owned by the service
+func (k *Kubernetes) ApplySecret(namespace, secretName string, data map[string]string) error {
+	secretsClient := k.connection.CoreV1().Secrets(namespace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+			Labels:    map[string]string{ManagedByLabel: ManagedByValue},
+		},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: data,
+	}
+
+	existing, err := secretsClient.Get(context.TODO(), secretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err = secretsClient.Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create secret %s in namespace %s: %w", secretName, namespace, err)
+		}
+		fmt.Printf("Successfully created secret %s in namespace %s\n", secretName, namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	if existing.Labels[ManagedByLabel] != ManagedByValue {
+		return fmt.Errorf("secret %s in namespace %s is not managed by %s", secretName, namespace, ManagedByValue)
+	}
+
+	// Replace the whole data set so removed keys don't linger
+	existing.Data = nil
+	existing.StringData = data
+	if _, err = secretsClient.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	fmt.Printf("Successfully updated secret %s in namespace %s\n", secretName, namespace)
+	return nil
+}
+
+// ListSecrets fetches the Opaque secrets owned by the service in the specified namespace, registry secrets are listed on their own
+func (k *Kubernetes) ListSecrets(namespace string) ([]corev1.Secret, error) {
+	secrets, err := k.connection.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: managedBySelector,
+		FieldSelector: fmt.Sprintf("type=%s", corev1.SecretTypeOpaque),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return secrets.Items, nil
+}
+
+// GetSecret fetches a secret owned by the service
+func (k *Kubernetes) GetSecret(namespace, secretName string) (*corev1.Secret, error) {
+	secret, err := k.connection.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	if secret.Labels[ManagedByLabel] != ManagedByValue {
+		return nil, fmt.Errorf("secret %s in namespace %s is not managed by %s", secretName, namespace, ManagedByValue)
+	}
+	return secret, nil
+}
+
+// DeleteSecret deletes a secret owned by the service
+func (k *Kubernetes) DeleteSecret(namespace, secretName string) error {
+	if _, err := k.GetSecret(namespace, secretName); err != nil {
+		if errors.IsNotFound(err) {
+			fmt.Printf("Secret %s does not exist in namespace %s\n", secretName, namespace)
+			return nil
+		}
+		return err
+	}
+	err := k.connection.CoreV1().Secrets(namespace).Delete(context.TODO(), secretName, metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to delete secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	fmt.Printf("Successfully deleted secret %s in namespace %s\n", secretName, namespace)
+	return nil
+}
+
+// ApplyConfigMap creates or replaces a config map owned by the service
+func (k *Kubernetes) ApplyConfigMap(namespace, configMapName string, data map[string]string) error {
+	configMapsClient := k.connection.CoreV1().ConfigMaps(namespace)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: namespace,
+			Labels:    map[string]string{ManagedByLabel: ManagedByValue},
+		},
+		Data: data,
+	}
+
+	existing, err := configMapsClient.Get(context.TODO(), configMapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err = configMapsClient.Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create config map %s in namespace %s: %w", configMapName, namespace, err)
+		}
+		fmt.Printf("Successfully created config map %s in namespace %s\n", configMapName, namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get config map %s in namespace %s: %w", configMapName, namespace, err)
+	}
+	if existing.Labels[ManagedByLabel] != ManagedByValue {
+		return fmt.Errorf("config map %s in namespace %s is not managed by %s", configMapName, namespace, ManagedByValue)
+	}
+
+	existing.Data = data
+	if _, err = configMapsClient.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update config map %s in namespace %s: %w", configMapName, namespace, err)
+	}
+	fmt.Printf("Successfully updated config map %s in namespace %s\n", configMapName, namespace)
+	return nil
+}
+
+// ListConfigMaps fetches the config maps owned by the service in the specified namespace
+func (k *Kubernetes) ListConfigMaps(namespace string) ([]corev1.ConfigMap, error) {
+	configMaps, err := k.connection.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: managedBySelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config maps: %w", err)
+	}
+	return configMaps.Items, nil
+}
+
+// GetConfigMap fetches a config map owned by the service
+func (k *Kubernetes) GetConfigMap(namespace, configMapName string) (*corev1.ConfigMap, error) {
+	configMap, err := k.connection.CoreV1().ConfigMaps(namespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config map %s in namespace %s: %w", configMapName, namespace, err)
+	}
+	if configMap.Labels[ManagedByLabel] != ManagedByValue {
+		return nil, fmt.Errorf("config map %s in namespace %s is not managed by %s", configMapName, namespace, ManagedByValue)
+	}
+	return configMap, nil
+}
+
+// DeleteConfigMap deletes a config map owned by the service
+func (k *Kubernetes) DeleteConfigMap(namespace, configMapName string) error {
+	if _, err := k.GetConfigMap(namespace, configMapName); err != nil {
+		if errors.IsNotFound(err) {
+			fmt.Printf("Config map %s does not exist in namespace %s\n", configMapName, namespace)
+			return nil
+		}
+		return err
+	}
+	err := k.connection.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), configMapName, metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to delete config map %s in namespace %s: %w", configMapName, namespace, err)
+	}
+	fmt.Printf("Successfully deleted config map %s in namespace %s\n", configMapName, namespace)
+	return nil
+}
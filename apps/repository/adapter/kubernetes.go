@@ -2,27 +2,41 @@ package adapter
 
 import (
 	"context"
+	"deployment-service/constants"
 	"fmt"
+	"io"
 	"sort"
+	"strconv"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/ptr"
 )
 
+const (
+	// RevisionAnnotation is the revision annotation the deployment controller sets on deployments and replica sets
+	RevisionAnnotation = "deployment.kubernetes.io/revision"
+	// ProgressDeadlineExceededReason is set on the Progressing condition once progressDeadlineSeconds elapses
+	ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+	// RestartedAtAnnotation is the pod template annotation `kubectl rollout restart` sets to roll all pods
+	RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
 // NewKubernetes initializes the Kubernetes adapter
 func NewKubernetes(client *kubernetes.Clientset) *Kubernetes {
 	return &Kubernetes{connection: client}
 }
 
-// ListDeployments fetches deployments in the specified namespace
+// ListDeployments fetches all deployments in the specified namespace
 func (k *Kubernetes) ListDeployments(namespace string) ([]appsv1.Deployment, error) {
 	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
 	deployments, err := deploymentsClient.List(context.TODO(), metav1.ListOptions{})
@@ -32,7 +46,25 @@ func (k *Kubernetes) ListDeployments(namespace string) ([]appsv1.Deployment, err
 	return deployments.Items, nil
 }
 
-// ListServices fetches services in the specified namespace
+// ListManagedDeployments fetches the deployments this service manages in the specified namespace,
+// from the cache once it has synced
+func (k *Kubernetes) ListManagedDeployments(namespace string) ([]appsv1.Deployment, error) {
+	if cache := k.cache.Load(); cache != nil {
+		deployments, err := cache.deployments.Lister().Deployments(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		return cachedItems(deployments), nil
+	}
+	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
+	deployments, err := deploymentsClient.List(context.TODO(), metav1.ListOptions{LabelSelector: managedBySelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	return deployments.Items, nil
+}
+
+// ListServices fetches all services in the specified namespace
 func (k *Kubernetes) ListServices(namespace string) ([]corev1.Service, error) {
 	servicesClient := k.connection.CoreV1().Services(namespace)
 	services, err := servicesClient.List(context.TODO(), metav1.ListOptions{})
@@ -42,6 +74,24 @@ func (k *Kubernetes) ListServices(namespace string) ([]corev1.Service, error) {
 	return services.Items, nil
 }
 
+// ListManagedServices fetches the services this service manages in the specified namespace,
+// from the cache once it has synced
+func (k *Kubernetes) ListManagedServices(namespace string) ([]corev1.Service, error) {
+	if cache := k.cache.Load(); cache != nil {
+		services, err := cache.services.Lister().Services(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		return cachedItems(services), nil
+	}
+	servicesClient := k.connection.CoreV1().Services(namespace)
+	services, err := servicesClient.List(context.TODO(), metav1.ListOptions{LabelSelector: managedBySelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	return services.Items, nil
+}
+
 // ListNamespaces fetches all namespaces in the cluster
 func (k *Kubernetes) ListNamespaces() ([]corev1.Namespace, error) {
 	namespacesClient := k.connection.CoreV1().Namespaces()
@@ -99,37 +149,6 @@ func (k *Kubernetes) UpdateDeploymentByNameReplicas(namespace, deploymentName st
 	return nil
 }
 
-// UpdateDeploymentByNameImageVersion updates the image version for a specified deployment
-func (k *Kubernetes) UpdateDeploymentByNameImageVersion(namespace, deploymentName, containerName, newImageVersion string) error {
-	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
-	deployment, err := deploymentsClient.Get(context.TODO(), deploymentName, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get deployment: %w", err)
-	}
-
-	// Update the image for the specified container in the deployment
-	updated := false
-	for i, container := range deployment.Spec.Template.Spec.Containers {
-		if container.Name == containerName {
-			deployment.Spec.Template.Spec.Containers[i].Image = newImageVersion
-			updated = true
-			break
-		}
-	}
-
-	if !updated {
-		return fmt.Errorf("container %s not found in deployment %s", containerName, deploymentName)
-	}
-
-	_, err = deploymentsClient.Update(context.TODO(), deployment, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update image version for deployment %s: %w", deploymentName, err)
-	}
-
-	fmt.Printf("Successfully updated image for container %s in deployment %s to %s\n", containerName, deploymentName, newImageVersion)
-	return nil
-}
-
 // DeleteDeploymentByName deletes a specified deployment from a namespace
 func (k *Kubernetes) DeleteDeploymentByName(namespace, deploymentName string) error {
 	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
@@ -144,22 +163,48 @@ func (k *Kubernetes) DeleteDeploymentByName(namespace, deploymentName string) er
 
 // Define KubernetesManifest struct
 type KubernetesManifest struct {
-	DesiredReplicas   int32                  `json:"desired_replicas"`
-	CurrentReplicas   int32                  `json:"current_replicas"`
-	AvailableReplicas int32                  `json:"current_replicas"`
-	Status            string                 `json:"status"`
-	Age               string                 `json:"age,omitempty"`
-	Image             string                 `json:"image,omitempty"`
-	Spec              map[string]interface{} `json:"spec,omitempty"`
+	DesiredReplicas    int32                  `json:"desired_replicas"`
+	CurrentReplicas    int32                  `json:"current_replicas"`
+	AvailableReplicas  int32                  `json:"available_replicas"`
+	UpdatedReplicas    int32                  `json:"updated_replicas"`
+	ReadyReplicas      int32                  `json:"ready_replicas"`
+	Generation         int64                  `json:"generation"`
+	ObservedGeneration int64                  `json:"observed_generation"`
+	Revision           int64                  `json:"revision"`
+	ProgressingReason  string                 `json:"progressing_reason,omitempty"`
+	ProgressingMessage string                 `json:"progressing_message,omitempty"`
+	ReplicaFailure     string                 `json:"replica_failure,omitempty"`
+	Status             string                 `json:"status"`
+	Age                string                 `json:"age,omitempty"`
+	Image              string                 `json:"image,omitempty"`
+	RestartedAt        string                 `json:"restarted_at,omitempty"`
+	MainContainer      corev1.Container       `json:"-"`
+	Containers         []corev1.Container     `json:"-"`
+	InitContainers     []corev1.Container     `json:"-"`
+	Spec               map[string]interface{} `json:"spec,omitempty"`
+}
+
+// MainContainer returns the container named after the deployment, the one its service and probes target.
+// Deployments created before sidecars were supported only have that container.
+func MainContainer(podSpec *corev1.PodSpec, deploymentName string) *corev1.Container {
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == deploymentName {
+			return &podSpec.Containers[i]
+		}
+	}
+	return &podSpec.Containers[0]
 }
 
 func (k *Kubernetes) GetDeploymentByName(namespace, deploymentName string) (*KubernetesManifest, error) {
-	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
-	deployment, err := deploymentsClient.Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	deployment, err := k.getDeployment(namespace, deploymentName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deployment %s: %w", deploymentName, err)
 	}
+	return newKubernetesManifest(deployment), nil
+}
 
+// newKubernetesManifest maps a deployment object to the KubernetesManifest struct
+func newKubernetesManifest(deployment *appsv1.Deployment) *KubernetesManifest {
 	// Default status
 	status := "Unknown"
 
@@ -171,7 +216,7 @@ func (k *Kubernetes) GetDeploymentByName(namespace, deploymentName string) (*Kub
 	currentReplicas := deployment.Status.Replicas
 	availableReplicas := deployment.Status.AvailableReplicas
 
-	image := deployment.Spec.Template.Spec.Containers[0].Image
+	image := MainContainer(&deployment.Spec.Template.Spec, deployment.Name).Image
 
 	// Determine the status based on available replicas
 	if availableReplicas == 0 {
@@ -189,42 +234,109 @@ func (k *Kubernetes) GetDeploymentByName(namespace, deploymentName string) (*Kub
 	fmt.Println("Latest Deployment Status:", status)
 
 	// Calculate the age of the deployment without seconds
-	creationTime := deployment.ObjectMeta.CreationTimestamp.Time
-	duration := time.Since(creationTime)
-	// Format the age in a human-readable way (days or minutes)
-	var age string
-	if duration.Hours() > 24 {
-		// More than 24 hours, display in days
-		age = fmt.Sprintf("%d days ago", int(duration.Hours()/24))
-	} else if duration.Minutes() > 0 {
-		// Less than 24 hours, display in minutes
-		age = fmt.Sprintf("%d minutes ago", int(duration.Minutes()))
-	} else {
-		// Less than a minute, display as "just now"
-		age = "Just now"
-	}
+	age := FormatAge(deployment.ObjectMeta.CreationTimestamp.Time)
 
 	// Map the relevant fields to KubernetesManifest struct
 	kubernetesManifest := &KubernetesManifest{
-		DesiredReplicas:   desiredReplicas,
-		CurrentReplicas:   currentReplicas,
-		AvailableReplicas: availableReplicas,
-		Status:            status,
-		Age:               age,
-		Image:             image,
-		Spec:              map[string]interface{}{"replicas": desiredReplicas},
+		DesiredReplicas:    desiredReplicas,
+		CurrentReplicas:    currentReplicas,
+		AvailableReplicas:  availableReplicas,
+		UpdatedReplicas:    deployment.Status.UpdatedReplicas,
+		ReadyReplicas:      deployment.Status.ReadyReplicas,
+		Generation:         deployment.Generation,
+		ObservedGeneration: deployment.Status.ObservedGeneration,
+		Revision:           getRevision(deployment.ObjectMeta),
+		Status:             status,
+		Age:                age,
+		Image:              image,
+		RestartedAt:        deployment.Spec.Template.Annotations[RestartedAtAnnotation],
+		MainContainer:      *MainContainer(&deployment.Spec.Template.Spec, deployment.Name),
+		Containers:         deployment.Spec.Template.Spec.Containers,
+		InitContainers:     deployment.Spec.Template.Spec.InitContainers,
+		Spec:               map[string]interface{}{"replicas": desiredReplicas},
+	}
+
+	// Pick up the rollout conditions reported by the deployment controller
+	for _, condition := range deployment.Status.Conditions {
+		switch condition.Type {
+		case appsv1.DeploymentProgressing:
+			kubernetesManifest.ProgressingReason = condition.Reason
+			kubernetesManifest.ProgressingMessage = condition.Message
+		case appsv1.DeploymentReplicaFailure:
+			if condition.Status == corev1.ConditionTrue {
+				kubernetesManifest.ReplicaFailure = condition.Message
+			}
+		}
+	}
+
+	return kubernetesManifest
+}
+
+// RolloutResult mirrors the checks done by `kubectl rollout status` on top of the manifest
+func (m *KubernetesManifest) RolloutResult() (string, string) {
+	if m.Generation > m.ObservedGeneration {
+		return constants.ROLLOUT_PENDING, "waiting for deployment spec update to be observed"
+	}
+	if m.ProgressingReason == ProgressDeadlineExceededReason {
+		return constants.ROLLOUT_TIMED_OUT, m.ProgressingMessage
+	}
+	if m.ReplicaFailure != "" {
+		return constants.ROLLOUT_FAILED, m.ReplicaFailure
+	}
+	if m.UpdatedReplicas < m.DesiredReplicas {
+		return constants.ROLLOUT_IN_PROGRESS, fmt.Sprintf("%d out of %d new replicas have been updated", m.UpdatedReplicas, m.DesiredReplicas)
+	}
+	if m.CurrentReplicas > m.UpdatedReplicas {
+		return constants.ROLLOUT_IN_PROGRESS, fmt.Sprintf("%d old replicas are pending termination", m.CurrentReplicas-m.UpdatedReplicas)
+	}
+	if m.AvailableReplicas < m.UpdatedReplicas {
+		return constants.ROLLOUT_IN_PROGRESS, fmt.Sprintf("%d of %d updated replicas are available", m.AvailableReplicas, m.UpdatedReplicas)
+	}
+	return constants.ROLLOUT_SUCCESS, "successfully rolled out"
+}
+
+// DeploymentRolloutResult is RolloutResult for a deployment object, such as one listed from the cache
+func DeploymentRolloutResult(deployment *appsv1.Deployment) (string, string) {
+	return newKubernetesManifest(deployment).RolloutResult()
+}
+
+// WaitForDeploymentRevision waits until the deployment controller has observed the given generation
+// and returns the revision of the ReplicaSet that backs it
+func (k *Kubernetes) WaitForDeploymentRevision(namespace, deploymentName string, generation int64, timeout time.Duration) (int64, error) {
+	var revision int64
+	err := wait.PollUntilContextTimeout(context.TODO(), time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		deployment, err := k.connection.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if deployment.Status.ObservedGeneration < generation {
+			return false, nil
+		}
+		revision = getRevision(deployment.ObjectMeta)
+		return true, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get revision for deployment %s in namespace %s: %w", deploymentName, namespace, err)
 	}
+	return revision, nil
+}
 
-	return kubernetesManifest, nil
+// getRevision reads the revision annotation set by the deployment controller
+func getRevision(meta metav1.ObjectMeta) int64 {
+	revision, err := strconv.ParseInt(meta.Annotations[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
 }
 
-// Create namespace
-func (k *Kubernetes) CreateNamespaceIfNotExists(namespace string) error {
+// CreateNamespaceIfNotExists creates the namespace and reports whether it had to be created
+func (k *Kubernetes) CreateNamespaceIfNotExists(namespace string) (bool, error) {
 	// check if namespace is already created
 	_, err := k.connection.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err == nil {
 		fmt.Printf("Namespace %s already exists\n", namespace)
-		return nil
+		return false, nil
 	}
 	_, err = k.connection.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -232,14 +344,46 @@ func (k *Kubernetes) CreateNamespaceIfNotExists(namespace string) error {
 		},
 	}, metav1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+		return false, fmt.Errorf("failed to create namespace %s: %w", namespace, err)
 	}
 	fmt.Printf("Successfully created namespace %s\n", namespace)
-	return nil
+	return true, nil
+}
+
+// DeploymentOptions holds the container settings applied when creating a deployment
+type DeploymentOptions struct {
+	Resources corev1.ResourceRequirements
+	Env       []corev1.EnvVar
+	Liveness  *corev1.Probe
+	Readiness *corev1.Probe
+	Startup   *corev1.Probe
+	// Sidecars run next to the main container, init containers run to completion before it starts
+	Sidecars       []corev1.Container
+	InitContainers []corev1.Container
+	// ImagePullSecrets name the registry secrets the pods pull their images with
+	ImagePullSecrets []string
+}
+
+// LocalObjectReferences turns secret names into the references of a pod's imagePullSecrets
+func LocalObjectReferences(names []string) []corev1.LocalObjectReference {
+	var refs []corev1.LocalObjectReference
+	for _, name := range names {
+		refs = append(refs, corev1.LocalObjectReference{Name: name})
+	}
+	return refs
+}
+
+// LocalObjectNames is the inverse of LocalObjectReferences
+func LocalObjectNames(refs []corev1.LocalObjectReference) []string {
+	names := []string{}
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
 }
 
 func (k *Kubernetes) CreateDeployment(namespace, deploymentName, image string,
-	replicas int32, containerPort int32, req_cpu, req_memory string) error {
+	replicas int32, containerPort int32, options DeploymentOptions) error {
 	// Check if deployment already exists
 	_, err := k.connection.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
 	if err == nil {
@@ -253,7 +397,8 @@ func (k *Kubernetes) CreateDeployment(namespace, deploymentName, image string,
 			Name:      deploymentName,
 			Namespace: namespace,
 			Labels: map[string]string{
-				"app": deploymentName,
+				"app":          deploymentName,
+				ManagedByLabel: ManagedByValue,
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
@@ -277,6 +422,7 @@ func (k *Kubernetes) CreateDeployment(namespace, deploymentName, image string,
 					},
 				},
 				Spec: corev1.PodSpec{
+					ImagePullSecrets: LocalObjectReferences(options.ImagePullSecrets),
 					Volumes: []corev1.Volume{
 						{
 							Name: "tmpfs-storage",
@@ -296,16 +442,11 @@ func (k *Kubernetes) CreateDeployment(namespace, deploymentName, image string,
 									ContainerPort: containerPort,
 								},
 							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse(req_cpu),
-									corev1.ResourceMemory: resource.MustParse(req_memory),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("0.5"),
-									corev1.ResourceMemory: resource.MustParse("0.5Gi"),
-								},
-							},
+							Resources:      options.Resources,
+							Env:            options.Env,
+							LivenessProbe:  options.Liveness,
+							ReadinessProbe: options.Readiness,
+							StartupProbe:   options.Startup,
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "tmpfs-storage",
@@ -317,27 +458,45 @@ func (k *Kubernetes) CreateDeployment(namespace, deploymentName, image string,
 							},
 						},
 					},
+					InitContainers: options.InitContainers,
 				},
 			},
 		},
 	}
 
+	deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, options.Sidecars...)
+
 	// Create the deployment
-	_, err = k.connection.AppsV1().Deployments(namespace).Create(context.TODO(), deployment, metav1.CreateOptions{})
+	created, err := k.connection.AppsV1().Deployments(namespace).Create(context.TODO(), deployment, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create deployment %s in namespace %s: %w", deploymentName, namespace, err)
 	}
+	k.awaitCached(created)
 
 	fmt.Printf("Successfully created deployment %s in namespace %s\n", deploymentName, namespace)
 
 	return nil
 }
 
+// FormatAge formats the age of an object in a human-readable way (days or minutes)
+func FormatAge(creationTime time.Time) string {
+	duration := time.Since(creationTime)
+	if duration.Hours() > 24 {
+		// More than 24 hours, display in days
+		return fmt.Sprintf("%d days ago", int(duration.Hours()/24))
+	} else if duration.Minutes() > 0 {
+		// Less than 24 hours, display in minutes
+		return fmt.Sprintf("%d minutes ago", int(duration.Minutes()))
+	}
+	// Less than a minute, display as "just now"
+	return "Just now"
+}
+
 // Helper function to create a pointer for int32 values
 func int32Ptr(i int32) *int32 { return &i }
 
-// CreateService creates a Kubernetes Service for a specified Deployment.
-func (k *Kubernetes) CreateService(namespace, serviceName, deploymentName string, servicePort, containerPort int32) error {
+// CreateService creates a Kubernetes Service of the given type for a specified Deployment.
+func (k *Kubernetes) CreateService(namespace, serviceName, deploymentName string, servicePort, containerPort int32, serviceType corev1.ServiceType) error {
 	// Check if the Service already exists
 	_, err := k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
 	if err == nil {
@@ -350,6 +509,7 @@ func (k *Kubernetes) CreateService(namespace, serviceName, deploymentName string
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceName,
 			Namespace: namespace,
+			Labels:    map[string]string{ManagedByLabel: ManagedByValue},
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: map[string]string{
@@ -362,24 +522,48 @@ func (k *Kubernetes) CreateService(namespace, serviceName, deploymentName string
 					Protocol:   corev1.ProtocolTCP,
 				},
 			},
-			Type: corev1.ServiceTypeLoadBalancer,
+			Type: serviceType,
 		},
 	}
 
 	// Create the Service in the specified namespace
-	_, err = k.connection.CoreV1().Services(namespace).Create(context.TODO(), service, metav1.CreateOptions{})
+	created, err := k.connection.CoreV1().Services(namespace).Create(context.TODO(), service, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create service %s in namespace %s: %w", serviceName, namespace, err)
 	}
+	k.awaitCached(created)
 
 	fmt.Printf("Successfully created service %s in namespace %s\n", serviceName, namespace)
 	return nil
 }
 
+// SetServiceSelector replaces the pod selector of a service, switching its traffic in one update
+func (k *Kubernetes) SetServiceSelector(namespace, serviceName string, selector map[string]string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service, err := k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		service.Spec.Selector = selector
+		updated, err := k.connection.CoreV1().Services(namespace).Update(context.TODO(), service, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		k.awaitCached(updated)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update selector of service %s in namespace %s: %w", serviceName, namespace, err)
+	}
+
+	fmt.Printf("Successfully updated selector of service %s in namespace %s\n", serviceName, namespace)
+	return nil
+}
+
 // DeleteDeployment deletes a Kubernetes deployment in the specified namespace.
 func (k *Kubernetes) DeleteDeployment(namespace, deploymentName string) error {
 	// Check if the Deployment exists
-	_, err := k.connection.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	deployment, err := k.connection.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			fmt.Printf("Deployment %s does not exist in namespace %s\n", deploymentName, namespace)
@@ -393,6 +577,7 @@ func (k *Kubernetes) DeleteDeployment(namespace, deploymentName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete deployment %s in namespace %s: %w", deploymentName, namespace, err)
 	}
+	k.awaitUncached(deployment)
 
 	fmt.Printf("Successfully deleted deployment %s in namespace %s\n", deploymentName, namespace)
 	return nil
@@ -401,7 +586,7 @@ func (k *Kubernetes) DeleteDeployment(namespace, deploymentName string) error {
 // DeleteService deletes a Kubernetes Service in the specified namespace.
 func (k *Kubernetes) DeleteService(namespace, serviceName string) error {
 	// Check if the Service exists
-	_, err := k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	service, err := k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			fmt.Printf("Service %s does not exist in namespace %s\n", serviceName, namespace)
@@ -415,109 +600,361 @@ func (k *Kubernetes) DeleteService(namespace, serviceName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete service %s in namespace %s: %w", serviceName, namespace, err)
 	}
+	k.awaitUncached(service)
 
 	fmt.Printf("Successfully deleted service %s in namespace %s\n", serviceName, namespace)
 	return nil
 }
 
-// GetServiceInfo retrieves information about a Kubernetes Service, including its external IP.
+// GetServiceInfo retrieves the endpoint a Kubernetes Service is reachable at, based on how it is exposed.
 func (k *Kubernetes) GetServiceInfo(namespace, serviceName string) (string, error) {
 	// Retrieve the Service object
-	service, err := k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	service, err := k.getService(namespace, serviceName)
 	if err != nil {
 		return "", fmt.Errorf("failed to get service %s in namespace %s: %w", serviceName, namespace, err)
 	}
+	if len(service.Spec.Ports) == 0 {
+		return "", fmt.Errorf("service %s in namespace %s exposes no ports", serviceName, namespace)
+	}
+
+	// Services published through an ingress are reachable at the ingress host
+	if ingressName, ok := service.Annotations[IngressAnnotation]; ok {
+		return k.GetIngressInfo(namespace, ingressName)
+	}
+
+	// Retrieve the port the Service exposes
+	servicePort := service.Spec.Ports[0].Port
 
-	// Check for the external IP in the Service's status
-	var externalIP string
-	if len(service.Status.LoadBalancer.Ingress) > 0 {
-		// External IP is available
-		externalIP = service.Status.LoadBalancer.Ingress[0].IP
+	var endpoint string
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		// Check for the external IP in the Service's status
+		if len(service.Status.LoadBalancer.Ingress) == 0 {
+			// No external IP assigned yet, the load balancer is still being provisioned
+			return "", fmt.Errorf("no external IP available for service %s in namespace %s", serviceName, namespace)
+		}
+		externalIP := service.Status.LoadBalancer.Ingress[0].IP
 		if externalIP == "" {
 			// Sometimes external IP may be set as a hostname
 			externalIP = service.Status.LoadBalancer.Ingress[0].Hostname
 		}
-	} else {
-		// No external IP assigned (might still be pending or the Service type may not support it)
-		return "", fmt.Errorf("no external IP available for service %s in namespace %s", serviceName, namespace)
+		endpoint = fmt.Sprintf("http://%s:%d", externalIP, servicePort)
+	case corev1.ServiceTypeNodePort:
+		nodeIP, err := k.getNodeAddress()
+		if err != nil {
+			return "", err
+		}
+		endpoint = fmt.Sprintf("http://%s:%d", nodeIP, service.Spec.Ports[0].NodePort)
+	default:
+		// ClusterIP services are only reachable from inside the cluster
+		endpoint = fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", serviceName, namespace, servicePort)
 	}
 
-	// Retrieve the port the Service exposes
-	servicePort := service.Spec.Ports[0].Port
-
-	// Construct the endpoint URL
-	endpoint := fmt.Sprintf("http://%s:%d", externalIP, servicePort)
 	fmt.Printf("Service %s in namespace %s is accessible at %s\n", serviceName, namespace, endpoint)
 	return endpoint, nil
 }
 
-// GetLatestEvents retrieves the latest 10 events from a Kubernetes namespace
-func (k *Kubernetes) GetLatestEvents(namespace string, topK int) ([]string, error) {
-	// Retrieve the list of events in the given namespace
-	eventsList, err := k.connection.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{})
+// getNodeAddress returns an address NodePort services can be reached at, preferring external addresses
+func (k *Kubernetes) getNodeAddress() (string, error) {
+	nodes, err := k.ListNodes()
+	if err != nil {
+		return "", err
+	}
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP} {
+		for _, node := range nodes {
+			for _, address := range node.Status.Addresses {
+				if address.Type == addressType {
+					return address.Address, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("no node address available for node port services")
+}
+
+// ListEvents retrieves the events of a Kubernetes namespace matching the field selector
+func (k *Kubernetes) ListEvents(namespace, fieldSelector string) ([]corev1.Event, error) {
+	eventsList, err := k.connection.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fieldSelector,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events in namespace %s: %w", namespace, err)
 	}
+	return eventsList.Items, nil
+}
+
+// GetDeploymentReplicaSetNames returns the names of the ReplicaSets owned by a deployment
+func (k *Kubernetes) GetDeploymentReplicaSetNames(namespace, deploymentName string) ([]string, error) {
+	deployment, err := k.getDeployment(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	replicaSets, err := k.listOwnedReplicaSets(deployment)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, rs := range replicaSets {
+		names = append(names, rs.Name)
+	}
+	return names, nil
+}
 
-	// Sort events by timestamp (from most recent to least recent)
-	sort.SliceStable(eventsList.Items, func(i, j int) bool {
-		return eventsList.Items[i].LastTimestamp.After(eventsList.Items[j].LastTimestamp.Time)
+// UpdateDeploymentSpec applies mutate to the latest version of a deployment and updates it, retrying on conflicts.
+// It returns the generation of the updated deployment so the rollout can be tracked.
+func (k *Kubernetes) UpdateDeploymentSpec(namespace, deploymentName string, mutate func(deployment *appsv1.Deployment) error) (int64, error) {
+	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
+	var generation int64
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Retrieve the current deployment object
+		deployment, err := deploymentsClient.Get(context.TODO(), deploymentName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+		}
+		if err := mutate(deployment); err != nil {
+			return err
+		}
+		updated, err := deploymentsClient.Update(context.TODO(), deployment, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		k.awaitCached(updated)
+		generation = updated.Generation
+		return nil
 	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	return generation, nil
+}
 
-	// Extract the event descriptions
-	var events []string
-	for i, event := range eventsList.Items {
-		if i >= topK {
-			break
+// RestartDeployment rolls all pods of a deployment the same way `kubectl rollout restart` does,
+// by stamping the pod template with the restart time. It returns the generation and the restart time.
+func (k *Kubernetes) RestartDeployment(namespace, deploymentName string) (int64, time.Time, error) {
+	restartedAt := time.Now().UTC().Truncate(time.Second)
+	generation, err := k.UpdateDeploymentSpec(namespace, deploymentName, func(deployment *appsv1.Deployment) error {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
 		}
+		deployment.Spec.Template.Annotations[RestartedAtAnnotation] = restartedAt.Format(time.RFC3339)
+		return nil
+	})
+	if err != nil {
+		return 0, restartedAt, err
+	}
+
+	fmt.Printf("Successfully restarted deployment %s in namespace %s\n", deploymentName, namespace)
+	return generation, restartedAt, nil
+}
 
-		eventMsg := fmt.Sprintf("Event: %s | Reason: %s | Message: %s | Time: %s",
-			event.Name, event.Reason, event.Message, event.LastTimestamp.Time)
-		events = append(events, eventMsg)
+// UpdateDeploymentReplicasAndImage updates the replicas and image for a given deployment by name in a namespace.
+// It returns the generation of the updated deployment so the rollout can be tracked.
+func (k *Kubernetes) UpdateDeploymentReplicasAndImage(namespace, deploymentName string, replicas int32, image string) (int64, error) {
+	generation, err := k.UpdateDeploymentSpec(namespace, deploymentName, func(deployment *appsv1.Deployment) error {
+		deployment.Spec.Replicas = &replicas
+		MainContainer(&deployment.Spec.Template.Spec, deploymentName).Image = image
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	// Return the latest 10 events
-	return events, nil
+	fmt.Printf("Successfully updated replicas for deployment %s to %d\n", deploymentName, replicas)
+	return generation, nil
 }
 
 // UpdateDeploymentReplicas updates the number of replicas for a given deployment by name in a namespace.
-func (k *Kubernetes) UpdateDeploymentReplicasAndImage(namespace, deploymentName string, replicas int32, image string) error {
+func (k *Kubernetes) UpdateDeploymentImage(namespace, deploymentName string, image string) error {
 	// Retrieve the current deployment object
 	deployment, err := k.connection.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
 	}
-	// Update the number of replicas in the deployment spec
-	deployment.Spec.Replicas = &replicas
-	deployment.Spec.Template.Spec.Containers[0].Image = image
+
+	// Update the image of the main container in the deployment spec
+	MainContainer(&deployment.Spec.Template.Spec, deploymentName).Image = image
 
 	// Update the deployment with the new number of replicas
-	_, err = k.connection.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+	updated, err := k.connection.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to update replicas for deployment %s in namespace %s: %w", deploymentName, namespace, err)
 	}
+	k.awaitCached(updated)
 
-	fmt.Printf("Successfully updated replicas for deployment %s to %d\n", deploymentName, replicas)
+	fmt.Printf("Successfully updated image for deployment %s to %s\n", deploymentName, image)
 	return nil
 }
 
-// UpdateDeploymentReplicas updates the number of replicas for a given deployment by name in a namespace.
-func (k *Kubernetes) UpdateDeploymentImage(namespace, deploymentName string, image string) error {
-	// Retrieve the current deployment object
+// DeploymentRevision describes a revision of a deployment, backed by one of its ReplicaSets
+type DeploymentRevision struct {
+	Revision       int64     `json:"revision"`
+	ReplicaSetName string    `json:"replica_set_name"`
+	Images         []string  `json:"images"`
+	Replicas       int32     `json:"replicas"`
+	ChangeCause    string    `json:"change_cause,omitempty"`
+	Current        bool      `json:"current"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// listOwnedReplicaSets lists the ReplicaSets controlled by the given deployment, newest revision first
+func (k *Kubernetes) listOwnedReplicaSets(deployment *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector for deployment %s: %w", deployment.Name, err)
+	}
+	replicaSets, err := k.connection.AppsV1().ReplicaSets(deployment.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replica sets for deployment %s: %w", deployment.Name, err)
+	}
+
+	var owned []appsv1.ReplicaSet
+	for _, rs := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&rs); owner != nil && owner.UID == deployment.UID {
+			owned = append(owned, rs)
+		}
+	}
+	sort.SliceStable(owned, func(i, j int) bool {
+		return getRevision(owned[i].ObjectMeta) > getRevision(owned[j].ObjectMeta)
+	})
+	return owned, nil
+}
+
+// GetDeploymentRevisions returns the revision history of a deployment derived from its owned ReplicaSets
+func (k *Kubernetes) GetDeploymentRevisions(namespace, deploymentName string) ([]DeploymentRevision, error) {
 	deployment, err := k.connection.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	replicaSets, err := k.listOwnedReplicaSets(deployment)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update the number of replicas in the deployment spec
-	deployment.Spec.Template.Spec.Containers[0].Image = image
+	currentRevision := getRevision(deployment.ObjectMeta)
+	revisions := []DeploymentRevision{}
+	for _, rs := range replicaSets {
+		var images []string
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+		replicas := int32(0)
+		if rs.Spec.Replicas != nil {
+			replicas = *rs.Spec.Replicas
+		}
+		revision := getRevision(rs.ObjectMeta)
+		revisions = append(revisions, DeploymentRevision{
+			Revision:       revision,
+			ReplicaSetName: rs.Name,
+			Images:         images,
+			Replicas:       replicas,
+			ChangeCause:    rs.Annotations["kubernetes.io/change-cause"],
+			Current:        revision == currentRevision,
+			CreatedAt:      rs.CreationTimestamp.Time,
+		})
+	}
+	return revisions, nil
+}
 
-	// Update the deployment with the new number of replicas
-	_, err = k.connection.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+// RollbackDeployment restores the pod template of the given revision, the same way `kubectl rollout undo` does.
+// A revision of 0 rolls back to the revision before the current one.
+// It returns the restored pod template and the generation of the updated deployment.
+func (k *Kubernetes) RollbackDeployment(namespace, deploymentName string, revision int64) (*corev1.PodTemplateSpec, int64, error) {
+	deploymentsClient := k.connection.AppsV1().Deployments(namespace)
+	deployment, err := deploymentsClient.Get(context.TODO(), deploymentName, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to update replicas for deployment %s in namespace %s: %w", deploymentName, namespace, err)
+		return nil, 0, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	replicaSets, err := k.listOwnedReplicaSets(deployment)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	currentRevision := getRevision(deployment.ObjectMeta)
+	var target *appsv1.ReplicaSet
+	for i, rs := range replicaSets {
+		rsRevision := getRevision(rs.ObjectMeta)
+		if (revision == 0 && rsRevision < currentRevision) || (revision != 0 && rsRevision == revision) {
+			target = &replicaSets[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, 0, fmt.Errorf("unable to find revision %d for deployment %s", revision, deploymentName)
+	}
+	if getRevision(target.ObjectMeta) == currentRevision {
+		return nil, 0, fmt.Errorf("revision %d is already the current revision of deployment %s", currentRevision, deploymentName)
 	}
 
-	fmt.Printf("Successfully updated image for deployment %s to %d\n", deploymentName, image)
+	// Restore the pod template without the hash label the deployment controller adds to ReplicaSets
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	deployment.Spec.Template = *template
+
+	updated, err := deploymentsClient.Update(context.TODO(), deployment, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to rollback deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	k.awaitCached(updated)
+
+	fmt.Printf("Successfully rolled back deployment %s to revision %d\n", deploymentName, getRevision(target.ObjectMeta))
+	return template, updated.Generation, nil
+}
+
+// GetDeploymentPods lists the pods selected by a deployment's label selector
+func (k *Kubernetes) GetDeploymentPods(namespace, deploymentName string) ([]corev1.Pod, error) {
+	deployment, err := k.getDeployment(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector for deployment %s: %w", deploymentName, err)
+	}
+	pods, err := k.connection.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods for deployment %s: %w", deploymentName, err)
+	}
+	// The app label selector also matches the canary and blue/green deployments cloned from this one
+	var owned []corev1.Pod
+	for _, pod := range pods.Items {
+		if PodOwnedBy(&pod, deploymentName) {
+			owned = append(owned, pod)
+		}
+	}
+	return owned, nil
+}
+
+// PodOwnedBy reports whether a pod belongs to one of the ReplicaSets of the deployment.
+// Deployments name their ReplicaSets after themselves and the pod template hash the pods are labelled with.
+func PodOwnedBy(pod *corev1.Pod, deploymentName string) bool {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "ReplicaSet" {
+		return false
+	}
+	hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	return ok && owner.Name == deploymentName+"-"+hash
+}
+
+// DeletePod deletes a single pod, its ReplicaSet recreates it
+func (k *Kubernetes) DeletePod(namespace, podName string) error {
+	err := k.connection.CoreV1().Pods(namespace).Delete(context.TODO(), podName, metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to delete pod %s in namespace %s: %w", podName, namespace, err)
+	}
+
+	fmt.Printf("Successfully deleted pod %s from namespace %s\n", podName, namespace)
 	return nil
 }
+
+// GetPodLogStream opens the log stream of a pod container, the stream ends when ctx is cancelled
+func (k *Kubernetes) GetPodLogStream(ctx context.Context, namespace, podName string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	stream, err := k.connection.CoreV1().Pods(namespace).GetLogs(podName, options).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs for pod %s in namespace %s: %w", podName, namespace, err)
+	}
+	return stream, nil
+}
@@ -0,0 +1,130 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IngressAnnotation is set on a service published through an ingress, it holds the ingress name
+const IngressAnnotation = "deployment-service/ingress"
+
+// CreateIngress routes host and path to the given service port and marks the service as published through it.
+func (k *Kubernetes) CreateIngress(namespace, ingressName, serviceName, ingressClassName, host, path string, servicePort int32) error {
+	// Check if the Ingress already exists
+	_, err := k.connection.NetworkingV1().Ingresses(namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err == nil {
+		fmt.Printf("Ingress %s already exists in namespace %s\n", ingressName, namespace)
+		return k.annotateIngressService(namespace, serviceName, ingressName)
+	}
+
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ingressName,
+			Namespace: namespace,
+			Labels:    map[string]string{ManagedByLabel: ManagedByValue},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     path,
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: serviceName,
+											Port: networkingv1.ServiceBackendPort{Number: servicePort},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if ingressClassName != "" {
+		ingress.Spec.IngressClassName = &ingressClassName
+	}
+
+	_, err = k.connection.NetworkingV1().Ingresses(namespace).Create(context.TODO(), ingress, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create ingress %s in namespace %s: %w", ingressName, namespace, err)
+	}
+
+	if err := k.annotateIngressService(namespace, serviceName, ingressName); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully created ingress %s in namespace %s\n", ingressName, namespace)
+	return nil
+}
+
+// annotateIngressService remembers the ingress on the service so GetServiceInfo reports the ingress endpoint
+func (k *Kubernetes) annotateIngressService(namespace, serviceName, ingressName string) error {
+	service, err := k.connection.CoreV1().Services(namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get service %s in namespace %s: %w", serviceName, namespace, err)
+	}
+	if service.Annotations[IngressAnnotation] == ingressName {
+		return nil
+	}
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[IngressAnnotation] = ingressName
+	updated, err := k.connection.CoreV1().Services(namespace).Update(context.TODO(), service, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to annotate service %s in namespace %s: %w", serviceName, namespace, err)
+	}
+	k.awaitCached(updated)
+	return nil
+}
+
+// GetIngressInfo retrieves the endpoint an Ingress is reachable at
+func (k *Kubernetes) GetIngressInfo(namespace, ingressName string) (string, error) {
+	ingress, err := k.connection.NetworkingV1().Ingresses(namespace).Get(context.TODO(), ingressName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get ingress %s in namespace %s: %w", ingressName, namespace, err)
+	}
+	if len(ingress.Spec.Rules) == 0 || ingress.Spec.Rules[0].HTTP == nil || len(ingress.Spec.Rules[0].HTTP.Paths) == 0 {
+		return "", fmt.Errorf("ingress %s in namespace %s has no rules", ingressName, namespace)
+	}
+
+	host := ingress.Spec.Rules[0].Host
+	if host == "" {
+		// Without a host the ingress is reachable at the address of the ingress controller
+		if len(ingress.Status.LoadBalancer.Ingress) == 0 {
+			return "", fmt.Errorf("no address available for ingress %s in namespace %s", ingressName, namespace)
+		}
+		host = ingress.Status.LoadBalancer.Ingress[0].IP
+		if host == "" {
+			host = ingress.Status.LoadBalancer.Ingress[0].Hostname
+		}
+	}
+	return fmt.Sprintf("http://%s%s", host, ingress.Spec.Rules[0].HTTP.Paths[0].Path), nil
+}
+
+// DeleteIngress deletes a Kubernetes Ingress in the specified namespace.
+func (k *Kubernetes) DeleteIngress(namespace, ingressName string) error {
+	err := k.connection.NetworkingV1().Ingresses(namespace).Delete(context.TODO(), ingressName, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			fmt.Printf("Ingress %s does not exist in namespace %s\n", ingressName, namespace)
+			return nil
+		}
+		return fmt.Errorf("failed to delete ingress %s in namespace %s: %w", ingressName, namespace, err)
+	}
+
+	fmt.Printf("Successfully deleted ingress %s in namespace %s\n", ingressName, namespace)
+	return nil
+}
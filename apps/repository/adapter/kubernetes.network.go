@@ -0,0 +1,161 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// names of the policies installed in every tenant namespace
+const (
+	DenyIngressPolicyName            = "default-deny-ingress"
+	AllowSameNamespacePolicyName     = "allow-same-namespace"
+	AllowIngressControllerPolicyName = "allow-from-ingress-controller"
+)
+
+// namespaceNameLabel is set on every namespace by the api server
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// NetworkPeer allows the pods of a deployment in another namespace to reach the given ports
+type NetworkPeer struct {
+	Namespace string
+	App       string
+	Ports     []int32
+}
+
+// ApplyDefaultNetworkPolicies isolates a tenant namespace: ingress is denied unless it comes
+// from the same namespace or from the ingress controller namespace.
+func (k *Kubernetes) ApplyDefaultNetworkPolicies(namespace, ingressControllerNamespace string) error {
+	ingressOnly := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	policies := map[string]networkingv1.NetworkPolicySpec{
+		DenyIngressPolicyName: {
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: ingressOnly,
+		},
+		AllowSameNamespacePolicyName: {
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: ingressOnly,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{},
+				}},
+			}},
+		},
+		AllowIngressControllerPolicyName: {
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: ingressOnly,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{namespaceNameLabel: ingressControllerNamespace},
+					},
+				}},
+			}},
+		},
+	}
+	for name, spec := range policies {
+		if err := k.applyNetworkPolicy(namespace, name, map[string]string{ManagedByLabel: ManagedByValue}, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyDeploymentNetworkPolicy opens ports of a deployment's pods on top of the namespace defaults.
+// Public ports are reachable from anywhere, peer ports only from the pods of the peer deployment.
+func (k *Kubernetes) ApplyDeploymentNetworkPolicy(namespace, policyName, deploymentName string, publicPorts []int32, peers []NetworkPeer) error {
+	var rules []networkingv1.NetworkPolicyIngressRule
+	if len(publicPorts) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{Ports: networkPolicyPorts(publicPorts)})
+	}
+	for _, peer := range peers {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{namespaceNameLabel: peer.Namespace},
+				},
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": peer.App},
+				},
+			}},
+			Ports: networkPolicyPorts(peer.Ports),
+		})
+	}
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": deploymentName},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress:     rules,
+	}
+	labels := map[string]string{
+		"app":          deploymentName,
+		ManagedByLabel: ManagedByValue,
+	}
+	return k.applyNetworkPolicy(namespace, policyName, labels, spec)
+}
+
+func networkPolicyPorts(ports []int32) []networkingv1.NetworkPolicyPort {
+	var policyPorts []networkingv1.NetworkPolicyPort
+	for _, port := range ports {
+		protocol := corev1.ProtocolTCP
+		portValue := intstr.FromInt32(port)
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{
+			Protocol: &protocol,
+			Port:     &portValue,
+		})
+	}
+	return policyPorts
+}
+
+// applyNetworkPolicy creates the network policy or replaces the spec of an existing one
+func (k *Kubernetes) applyNetworkPolicy(namespace, policyName string, labels map[string]string, spec networkingv1.NetworkPolicySpec) error {
+	policyClient := k.connection.NetworkingV1().NetworkPolicies(namespace)
+
+	existing, err := policyClient.Get(context.TODO(), policyName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		policy := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      policyName,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Spec: spec,
+		}
+		if _, err = policyClient.Create(context.TODO(), policy, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create network policy %s in namespace %s: %w", policyName, namespace, err)
+		}
+		fmt.Printf("Successfully created network policy %s in namespace %s\n", policyName, namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get network policy %s in namespace %s: %w", policyName, namespace, err)
+	}
+
+	existing.Spec = spec
+	if _, err = policyClient.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update network policy %s in namespace %s: %w", policyName, namespace, err)
+	}
+	fmt.Printf("Successfully updated network policy %s in namespace %s\n", policyName, namespace)
+	return nil
+}
+
+// DeleteNetworkPolicy deletes a network policy in the specified namespace.
+func (k *Kubernetes) DeleteNetworkPolicy(namespace, policyName string) error {
+	err := k.connection.NetworkingV1().NetworkPolicies(namespace).Delete(context.TODO(), policyName, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			fmt.Printf("Network policy %s does not exist in namespace %s\n", policyName, namespace)
+			return nil
+		}
+		return fmt.Errorf("failed to delete network policy %s in namespace %s: %w", policyName, namespace, err)
+	}
+
+	fmt.Printf("Successfully deleted network policy %s in namespace %s\n", policyName, namespace)
+	return nil
+}
@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegistryServerAnnotation keeps the registry host of a pull secret readable without decoding its config
+const RegistryServerAnnotation = "deployment-service/registry-server"
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth"`
+}
+
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// RegistryUsername returns the user a pull secret logs in to its registry as
+func RegistryUsername(secret corev1.Secret) string {
+	var config dockerConfig
+	if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+		return ""
+	}
+	return config.Auths[secret.Annotations[RegistryServerAnnotation]].Username
+}
+
+// ApplyRegistrySecret creates or replaces a kubernetes.io/dockerconfigjson secret owned by the service
+func (k *Kubernetes) ApplyRegistrySecret(namespace, secretName, server, username, password, email string) error {
+	configJSON, err := json.Marshal(dockerConfig{Auths: map[string]dockerConfigEntry{
+		server: {
+			Username: username,
+			Password: password,
+			Email:    email,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+		},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to encode docker config for secret %s: %w", secretName, err)
+	}
+	secretsClient := k.connection.CoreV1().Secrets(namespace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secretName,
+			Namespace:   namespace,
+			Labels:      map[string]string{ManagedByLabel: ManagedByValue},
+			Annotations: map[string]string{RegistryServerAnnotation: server},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: configJSON},
+	}
+
+	existing, err := secretsClient.Get(context.TODO(), secretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err = secretsClient.Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create registry secret %s in namespace %s: %w", secretName, namespace, err)
+		}
+		fmt.Printf("Successfully created registry secret %s in namespace %s\n", secretName, namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get registry secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	if existing.Labels[ManagedByLabel] != ManagedByValue || existing.Type != corev1.SecretTypeDockerConfigJson {
+		return fmt.Errorf("secret %s in namespace %s is not a registry secret managed by %s", secretName, namespace, ManagedByValue)
+	}
+
+	existing.Annotations = secret.Annotations
+	existing.Data = secret.Data
+	if _, err = secretsClient.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update registry secret %s in namespace %s: %w", secretName, namespace, err)
+	}
+	fmt.Printf("Successfully updated registry secret %s in namespace %s\n", secretName, namespace)
+	return nil
+}
+
+// ListRegistrySecrets fetches the registry secrets owned by the service in the specified namespace
+func (k *Kubernetes) ListRegistrySecrets(namespace string) ([]corev1.Secret, error) {
+	secrets, err := k.connection.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: managedBySelector,
+		FieldSelector: fmt.Sprintf("type=%s", corev1.SecretTypeDockerConfigJson),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry secrets: %w", err)
+	}
+	return secrets.Items, nil
+}
+
+// DeleteRegistrySecret deletes a registry secret owned by the service
+func (k *Kubernetes) DeleteRegistrySecret(namespace, secretName string) error {
+	secret, err := k.GetSecret(namespace, secretName)
+	if errors.IsNotFound(err) {
+		fmt.Printf("Registry secret %s does not exist in namespace %s\n", secretName, namespace)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if secret.Labels[ManagedByLabel] != ManagedByValue || secret.Type != corev1.SecretTypeDockerConfigJson {
+		return fmt.Errorf("secret %s in namespace %s is not a registry secret managed by %s", secretName, namespace, ManagedByValue)
+	}
+	return k.DeleteSecret(namespace, secretName)
+}
@@ -0,0 +1,81 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyResourceQuota creates or replaces the hard limits of a ResourceQuota in a tenant namespace
+func (k *Kubernetes) ApplyResourceQuota(namespace, quotaName string, hard corev1.ResourceList) error {
+	quotasClient := k.connection.CoreV1().ResourceQuotas(namespace)
+	existing, err := quotasClient.Get(context.TODO(), quotaName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      quotaName,
+				Namespace: namespace,
+				Labels:    map[string]string{ManagedByLabel: ManagedByValue},
+			},
+			Spec: corev1.ResourceQuotaSpec{Hard: hard},
+		}
+		if _, err = quotasClient.Create(context.TODO(), quota, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create resource quota %s in namespace %s: %w", quotaName, namespace, err)
+		}
+		fmt.Printf("Successfully created resource quota %s in namespace %s\n", quotaName, namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get resource quota %s in namespace %s: %w", quotaName, namespace, err)
+	}
+
+	existing.Spec.Hard = hard
+	if _, err = quotasClient.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update resource quota %s in namespace %s: %w", quotaName, namespace, err)
+	}
+	fmt.Printf("Successfully updated resource quota %s in namespace %s\n", quotaName, namespace)
+	return nil
+}
+
+// GetResourceQuota fetches a ResourceQuota, its status holds the used and hard limits
+func (k *Kubernetes) GetResourceQuota(namespace, quotaName string) (*corev1.ResourceQuota, error) {
+	quota, err := k.connection.CoreV1().ResourceQuotas(namespace).Get(context.TODO(), quotaName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource quota %s in namespace %s: %w", quotaName, namespace, err)
+	}
+	return quota, nil
+}
+
+// ApplyLimitRange creates or replaces a LimitRange in a tenant namespace
+func (k *Kubernetes) ApplyLimitRange(namespace, limitRangeName string, limits ...corev1.LimitRangeItem) error {
+	limitRangesClient := k.connection.CoreV1().LimitRanges(namespace)
+	existing, err := limitRangesClient.Get(context.TODO(), limitRangeName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		limitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      limitRangeName,
+				Namespace: namespace,
+				Labels:    map[string]string{ManagedByLabel: ManagedByValue},
+			},
+			Spec: corev1.LimitRangeSpec{Limits: limits},
+		}
+		if _, err = limitRangesClient.Create(context.TODO(), limitRange, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create limit range %s in namespace %s: %w", limitRangeName, namespace, err)
+		}
+		fmt.Printf("Successfully created limit range %s in namespace %s\n", limitRangeName, namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get limit range %s in namespace %s: %w", limitRangeName, namespace, err)
+	}
+
+	existing.Spec.Limits = limits
+	if _, err = limitRangesClient.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update limit range %s in namespace %s: %w", limitRangeName, namespace, err)
+	}
+	fmt.Printf("Successfully updated limit range %s in namespace %s\n", limitRangeName, namespace)
+	return nil
+}
@@ -0,0 +1,43 @@
+package adapter
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func ownedPod(kind, owner, hash string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Labels: map[string]string{"app": "web"}}}
+	if hash != "" {
+		pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = hash
+	}
+	if owner != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: ptr.To(true)}}
+	}
+	return pod
+}
+
+func TestPodOwnedBy(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{"replica set of the deployment", ownedPod("ReplicaSet", "web-5d8f7c", "5d8f7c"), true},
+		{"replica set of the canary", ownedPod("ReplicaSet", "web-canary-7b9d6f", "7b9d6f"), false},
+		{"replica set of a blue/green color", ownedPod("ReplicaSet", "web-green-7b9d6f", "7b9d6f"), false},
+		{"hash of another replica set", ownedPod("ReplicaSet", "web-5d8f7c", "7b9d6f"), false},
+		{"missing hash label", ownedPod("ReplicaSet", "web-5d8f7c", ""), false},
+		{"not a replica set", ownedPod("StatefulSet", "web-5d8f7c", "5d8f7c"), false},
+		{"no owner", ownedPod("", "", "5d8f7c"), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PodOwnedBy(tc.pod, "web"); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+const (
+	MetricsSourceMetricsServer = "metrics-server"
+	MetricsSourceFake          = "fake"
+)
+
+// PodUsage is the live cpu and memory usage of a pod, summed over its containers
+type PodUsage struct {
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// MetricsProvider reads the live usage of pods, keyed by pod name
+type MetricsProvider interface {
+	Source() string
+	GetPodUsage(namespace, labelSelector string) (map[string]PodUsage, error)
+}
+
+// MetricsServer reads pod usage from the metrics.k8s.io api served by metrics-server
+type MetricsServer struct {
+	connection *metricsclient.Clientset
+}
+
+func NewMetricsServer(client *metricsclient.Clientset) *MetricsServer {
+	return &MetricsServer{connection: client}
+}
+
+func (m *MetricsServer) Source() string {
+	return MetricsSourceMetricsServer
+}
+
+func (m *MetricsServer) GetPodUsage(namespace, labelSelector string) (map[string]PodUsage, error) {
+	podMetrics, err := m.connection.MetricsV1beta1().PodMetricses(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod metrics in namespace %s: %w", namespace, err)
+	}
+	usage := map[string]PodUsage{}
+	for _, pod := range podMetrics.Items {
+		var podUsage PodUsage
+		for _, container := range pod.Containers {
+			podUsage.CPU.Add(container.Usage[corev1.ResourceCPU])
+			podUsage.Memory.Add(container.Usage[corev1.ResourceMemory])
+		}
+		usage[pod.Name] = podUsage
+	}
+	return usage, nil
+}
+
+// FakeMetrics stands in for metrics-server on clusters that don't run it.
+// Pods missing from Usage report no usage.
+type FakeMetrics struct {
+	Usage map[string]PodUsage
+}
+
+func (m *FakeMetrics) Source() string {
+	return MetricsSourceFake
+}
+
+func (m *FakeMetrics) GetPodUsage(namespace, labelSelector string) (map[string]PodUsage, error) {
+	usage := map[string]PodUsage{}
+	for name, podUsage := range m.Usage {
+		usage[name] = podUsage
+	}
+	return usage, nil
+}
@@ -49,6 +49,12 @@ func (m *MongoDB) UpdateOne(collection string, filter bson.M, update bson.M) (*m
 	return col.UpdateOne(context.TODO(), filter, update)
 }
 
+// FindOneAndUpdate atomically updates a single document in the specified collection and returns it as it was before the update
+func (m *MongoDB) FindOneAndUpdate(collection string, filter bson.M, update bson.M) *mongo.SingleResult {
+	col := m.connection.Database(constants.MONGODB_NAME).Collection(collection)
+	return col.FindOneAndUpdate(context.TODO(), filter, update)
+}
+
 // UpdateMany updates multiple documents in the specified collection
 func (m *MongoDB) UpdateMany(collection string, filter bson.M, update bson.M) (*mongo.UpdateResult, error) {
 	col := m.connection.Database(constants.MONGODB_NAME).Collection(collection)
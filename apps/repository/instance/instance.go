@@ -2,6 +2,7 @@ package instance
 
 import (
 	"context"
+	"deployment-service/apps/repository/adapter"
 	"deployment-service/constants"
 	"deployment-service/logger"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 func GetRedisConnection() *redis.Client {
@@ -75,7 +77,7 @@ func GetMongoConnection() *mongo.Client {
 	return client
 }
 
-func GetKubernetesConnection() *kubernetes.Clientset {
+func getKubernetesConfig() *rest.Config {
 	var config *rest.Config
 	var err error
 
@@ -95,8 +97,12 @@ func GetKubernetesConnection() *kubernetes.Clientset {
 		}
 	}
 
+	return config
+}
+
+func GetKubernetesConnection() *kubernetes.Clientset {
 	// Initialize the Kubernetes clientset
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := kubernetes.NewForConfig(getKubernetesConfig())
 	if err != nil {
 		panic(fmt.Sprintf("Failed to create Kubernetes clientset: %v", err))
 	}
@@ -104,3 +110,25 @@ func GetKubernetesConnection() *kubernetes.Clientset {
 	fmt.Println("Kubernetes connection established successfully")
 	return clientset
 }
+
+// GetMetricsProvider returns the metrics-server client, or the fake provider when metrics-server
+// is disabled or, in auto mode, when the cluster doesn't serve the metrics api
+func GetMetricsProvider(kubernetesClient *kubernetes.Clientset) adapter.MetricsProvider {
+	switch constants.METRICS_PROVIDER {
+	case adapter.MetricsSourceFake:
+		fmt.Println("Using fake metrics provider")
+		return &adapter.FakeMetrics{}
+	case "auto":
+		if _, err := kubernetesClient.Discovery().ServerResourcesForGroupVersion("metrics.k8s.io/v1beta1"); err != nil {
+			fmt.Printf("Metrics api not available, using fake metrics provider: %v\n", err)
+			return &adapter.FakeMetrics{}
+		}
+	}
+
+	client, err := metricsclient.NewForConfig(getKubernetesConfig())
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create metrics clientset: %v", err))
+	}
+	fmt.Println("Metrics connection established successfully")
+	return adapter.NewMetricsServer(client)
+}
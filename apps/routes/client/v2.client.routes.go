@@ -15,22 +15,75 @@ func V2(group *gin.RouterGroup, repository *adapter.Repository) {
 	fmt.Println("Initialising frontend v1 group routes.")
 	v1ClientDeploymentsCtrl := v1.NewDeploymentController(repository)
 	v1ClientBuildsCrtrl := v1.NewBuildController(repository)
+	v1ClientConfigCtrl := v1.NewConfigController(repository)
 	group.Use(middlewares.ValidateHeaderSecrets(repository))
 	{
 		group.POST("/deployments/createns/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.CreateNamespace)
 		group.GET("/deployments/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDeploymentsByNamespace)
 		group.GET("/deployments/events/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetLatestEvents)
 		group.GET("/deployments/tenant/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetTenantKubernetesInfo)
+		// drift between the deployment records and the cluster, found by the reconciler or on demand
+		group.GET("/deployments/drift/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDrift)
+		group.POST("/deployments/drift/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.ReconcileNamespace)
 		// create a new deployment
 		group.POST("/deployments/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.CreateDeployment)
 		// Update a deployment replica
 		group.PUT("/deployments/", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.UpdateDeploymentByName)
+		// apply the full desired spec of a deployment, returns the fields it changed
+		group.PUT("/deployments/:deployment_name/spec", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.ApplyDeploymentSpec)
 		// get a deployment by name
 		group.GET("/deployments/:deployment_name", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDeploymentByName)
 		// delete a deployment by name
 		group.DELETE("/deployments/:deployment_name", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.DeleteDeployment)
+		// the recorded steps of the creates and deletes of a deployment
+		group.GET("/deployments/:deployment_name/operations", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDeploymentOperations)
+		// the change history of a deployment, newest first
+		group.GET("/deployments/:deployment_name/history", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDeploymentHistory)
+		// get the rollout status of a deployment
+		group.GET("/deployments/:deployment_name/rollout", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetRolloutStatus)
+		// get the revision history of a deployment
+		group.GET("/deployments/:deployment_name/revisions", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDeploymentRevisions)
+		// rollback a deployment to a previous revision
+		group.POST("/deployments/:deployment_name/rollback", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.RollbackDeployment)
+		group.POST("/deployments/:deployment_name/restart", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.RestartDeployment)
+		// scale a deployment to 0 and back to its previous replica count
+		group.POST("/deployments/:deployment_name/pause", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.PauseDeployment)
+		group.POST("/deployments/:deployment_name/resume", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.ResumeDeployment)
+		group.GET("/deployments/:deployment_name/pods", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDeploymentPods)
+		group.DELETE("/deployments/:deployment_name/pods/:pod_name", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.DeleteDeploymentPod)
+		// get or follow the logs of a deployment's pods
+		group.GET("/deployments/:deployment_name/logs", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetDeploymentLogs)
+		// manage the horizontal pod autoscaler of a deployment
+		group.POST("/deployments/:deployment_name/autoscaler", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.CreateAutoscaler)
+		group.PUT("/deployments/:deployment_name/autoscaler", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.UpdateAutoscaler)
+		group.GET("/deployments/:deployment_name/autoscaler", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetAutoscaler)
+		group.DELETE("/deployments/:deployment_name/autoscaler", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.DeleteAutoscaler)
+		group.GET("/deployments/:deployment_name/canary", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetCanary)
+		group.POST("/deployments/:deployment_name/canary/promote", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.PromoteCanary)
+		group.POST("/deployments/:deployment_name/canary/abort", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.AbortCanary)
+		group.GET("/deployments/:deployment_name/bluegreen", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetBlueGreen)
+		group.POST("/deployments/:deployment_name/bluegreen/switchback", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.SwitchBackBlueGreen)
+		// cron style replica schedules of a deployment
+		group.POST("/deployments/:deployment_name/schedules", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.CreateSchedule)
+		group.GET("/deployments/:deployment_name/schedules", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetSchedules)
+		group.PUT("/deployments/:deployment_name/schedules/:schedule_id", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.UpdateSchedule)
+		group.DELETE("/deployments/:deployment_name/schedules/:schedule_id", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.DeleteSchedule)
+		group.GET("/deployments/:deployment_name/schedules/:schedule_id/next", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetScheduleNextRuns)
+		group.GET("/deployments/:deployment_name/schedules/:schedule_id/runs", middlewares.ValidateJWT(repository), v1ClientDeploymentsCtrl.GetScheduleRuns)
 
 		group.POST("/build/scout/", middlewares.ValidateJWT(repository), v1ClientBuildsCrtrl.CreateNewRepoScout)
 		group.GET("/build/scout/", middlewares.ValidateJWT(repository), v1ClientBuildsCrtrl.GetAllRepoScouts)
+
+		// tenant managed secrets and config maps referenced by deployment env vars
+		group.POST("/secrets/", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.ApplySecret)
+		group.GET("/secrets/", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.ListSecrets)
+		group.DELETE("/secrets/:secret_name", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.DeleteSecret)
+		group.POST("/configmaps/", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.ApplyConfigMap)
+		group.GET("/configmaps/", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.ListConfigMaps)
+		group.DELETE("/configmaps/:config_map_name", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.DeleteConfigMap)
+		// private registry credentials the tenant's deployments pull their images with
+		group.POST("/registries/", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.ApplyRegistryCredential)
+		group.GET("/registries/", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.ListRegistryCredentials)
+		group.DELETE("/registries/:registry_name", middlewares.ValidateJWT(repository), v1ClientConfigCtrl.DeleteRegistryCredential)
 	}
 }
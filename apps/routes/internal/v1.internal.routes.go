@@ -13,8 +13,11 @@ import (
 func V1(group *gin.RouterGroup, repository *adapter.Repository) {
 	logger.ConsoleLogger.Debug("Initialising v1 internal group routes.")
 	v1PrivateEventLoggerCtrl := v1.NewEventLoggerController(repository)
+	v1PrivateTenantCtrl := v1.NewTenantController(repository)
 	group.Use(middlewares.ValidateHeaderSecrets(repository))
 	{
 		group.POST("/log/", v1PrivateEventLoggerCtrl.LogActivity)
+		group.PUT("/tenants/:namespace/quota", v1PrivateTenantCtrl.UpdateTenantQuota)
+		group.PUT("/tenants/:namespace/reconcile-policy", v1PrivateTenantCtrl.UpdateReconcilePolicy)
 	}
 }
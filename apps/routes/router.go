@@ -60,6 +60,7 @@ func (r *Router) setConfigRouters() {
 	// r.EnableAPILogger()
 	r.EnableCORS()
 	r.EnableRecover()
+	r.EnableRequestID()
 	r.RouterHealth()
 }
 
@@ -107,6 +108,11 @@ func (r *Router) EnableRecover() *Router {
 	return r
 }
 
+func (r *Router) EnableRequestID() *Router {
+	r.router.Use(middlewares.RequestID())
+	return r
+}
+
 func (r *Router) SetInternalRoutes(repository *adapter.Repository) {
 	v1Group := r.router.Group("v1/internal/")
 	internal.V1(v1Group, repository)
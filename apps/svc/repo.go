@@ -10,6 +10,8 @@ type ServiceRepository struct {
 	EventLoggerService *EventLoggerService
 	DeploymentService  *DeploymentService
 	BuildService       *BuildService
+	ConfigService      *ConfigService
+	TenantService      *TenantService
 }
 
 func NewServiceRepo(repository *adapter.Repository) *ServiceRepository {
@@ -17,5 +19,7 @@ func NewServiceRepo(repository *adapter.Repository) *ServiceRepository {
 		EventLoggerService: &EventLoggerService{repository},
 		DeploymentService:  &DeploymentService{repository},
 		BuildService:       &BuildService{repository},
+		ConfigService:      &ConfigService{repository},
+		TenantService:      &TenantService{repository},
 	}
 }
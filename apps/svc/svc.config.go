@@ -0,0 +1,154 @@
+package svc
+
+import (
+	adapter "deployment-service/apps/repository/adapter"
+	model_config "deployment-service/models/model.config"
+	model_deployment "deployment-service/models/model.deployment"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type ConfigService struct {
+	repository *adapter.Repository
+}
+
+// validateConfig checks the object name and keys before they are sent to kubernetes
+func validateConfig(payload model_config.TenantConfigReq) error {
+	if errs := validation.IsDNS1123Subdomain(payload.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s", payload.Name, strings.Join(errs, ", "))
+	}
+	if len(payload.Data) == 0 {
+		return errors.New("data must contain at least one key")
+	}
+	for key := range payload.Data {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a secret or config map in a stable order
+func sortedKeys[V any](data map[string]V) []string {
+	keys := []string{}
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ensureNotReferenced refuses to delete a secret or config map that a deployment still reads its env from,
+// in the main container, a sidecar or an init container
+func (svc ConfigService) ensureNotReferenced(namespace, field, name string) error {
+	count, err := svc.repository.MongoDB.CountDocuments("DEPLOYMENTS", bson.M{
+		"namespace": namespace,
+		"$or": bson.A{
+			bson.M{field: name},
+			bson.M{"sidecars." + field: name},
+			bson.M{"init_containers." + field: name},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check deployments referencing %s: %w", name, err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%s is still referenced by %d deployment(s)", name, count)
+	}
+	return nil
+}
+
+func (svc ConfigService) ApplySecret(namespace string, payload model_config.TenantConfigReq) error {
+	if err := validateConfig(payload); err != nil {
+		return err
+	}
+	return svc.repository.Kubernetes.ApplySecret(namespace, payload.Name, payload.Data)
+}
+
+func (svc ConfigService) ListSecrets(namespace string) ([]model_config.TenantConfigInfo, error) {
+	secrets, err := svc.repository.Kubernetes.ListSecrets(namespace)
+	if err != nil {
+		return nil, err
+	}
+	var result = []model_config.TenantConfigInfo{}
+	for _, secret := range secrets {
+		result = append(result, model_config.TenantConfigInfo{
+			Name:      secret.Name,
+			Keys:      sortedKeys(secret.Data),
+			CreatedAt: secret.CreationTimestamp.Time,
+		})
+	}
+	return result, nil
+}
+
+func (svc ConfigService) DeleteSecret(namespace, secretName string) error {
+	// Registry credentials are removed through their own api, which checks the deployments pulling with them
+	if secret, err := svc.repository.Kubernetes.GetSecret(namespace, secretName); err == nil && secret.Type == corev1.SecretTypeDockerConfigJson {
+		return fmt.Errorf("%s is a registry credential", secretName)
+	}
+	if err := svc.ensureNotReferenced(namespace, "env.secret_ref.name", secretName); err != nil {
+		return err
+	}
+	return svc.repository.Kubernetes.DeleteSecret(namespace, secretName)
+}
+
+func (svc ConfigService) ApplyConfigMap(namespace string, payload model_config.TenantConfigReq) error {
+	if err := validateConfig(payload); err != nil {
+		return err
+	}
+	return svc.repository.Kubernetes.ApplyConfigMap(namespace, payload.Name, payload.Data)
+}
+
+func (svc ConfigService) ListConfigMaps(namespace string) ([]model_config.TenantConfigInfo, error) {
+	configMaps, err := svc.repository.Kubernetes.ListConfigMaps(namespace)
+	if err != nil {
+		return nil, err
+	}
+	var result = []model_config.TenantConfigInfo{}
+	for _, configMap := range configMaps {
+		result = append(result, model_config.TenantConfigInfo{
+			Name:      configMap.Name,
+			Keys:      sortedKeys(configMap.Data),
+			CreatedAt: configMap.CreationTimestamp.Time,
+		})
+	}
+	return result, nil
+}
+
+func (svc ConfigService) DeleteConfigMap(namespace, configMapName string) error {
+	if err := svc.ensureNotReferenced(namespace, "env.config_map_ref.name", configMapName); err != nil {
+		return err
+	}
+	return svc.repository.Kubernetes.DeleteConfigMap(namespace, configMapName)
+}
+
+// ValidateEnvReferences checks that every referenced secret and config map is managed by the service and has the key
+func (svc ConfigService) ValidateEnvReferences(namespace string, env []model_deployment.EnvVar) error {
+	for _, e := range env {
+		if e.SecretRef != nil {
+			secret, err := svc.repository.Kubernetes.GetSecret(namespace, e.SecretRef.Name)
+			if err != nil {
+				return fmt.Errorf("env var %s: %w", e.Name, err)
+			}
+			if _, ok := secret.Data[e.SecretRef.Key]; !ok {
+				return fmt.Errorf("env var %s: key %s not found in secret %s", e.Name, e.SecretRef.Key, e.SecretRef.Name)
+			}
+		}
+		if e.ConfigMapRef != nil {
+			configMap, err := svc.repository.Kubernetes.GetConfigMap(namespace, e.ConfigMapRef.Name)
+			if err != nil {
+				return fmt.Errorf("env var %s: %w", e.Name, err)
+			}
+			if _, ok := configMap.Data[e.ConfigMapRef.Key]; !ok {
+				return fmt.Errorf("env var %s: key %s not found in config map %s", e.Name, e.ConfigMapRef.Key, e.ConfigMapRef.Name)
+			}
+		}
+	}
+	return nil
+}
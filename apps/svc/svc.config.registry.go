@@ -0,0 +1,86 @@
+package svc
+
+import (
+	adapter "deployment-service/apps/repository/adapter"
+	model_config "deployment-service/models/model.config"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func validateRegistryCredential(payload model_config.RegistryCredentialReq) error {
+	if errs := validation.IsDNS1123Subdomain(payload.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s", payload.Name, strings.Join(errs, ", "))
+	}
+	if payload.Server == "" || strings.ContainsAny(payload.Server, " \t\n") {
+		return fmt.Errorf("invalid registry server %q", payload.Server)
+	}
+	if payload.Username == "" || payload.Password == "" {
+		return errors.New("username and password are required")
+	}
+	return nil
+}
+
+func (svc ConfigService) ApplyRegistryCredential(namespace string, payload model_config.RegistryCredentialReq) error {
+	if err := validateRegistryCredential(payload); err != nil {
+		return err
+	}
+	return svc.repository.Kubernetes.ApplyRegistrySecret(namespace, payload.Name, payload.Server,
+		payload.Username, payload.Password, payload.Email)
+}
+
+func (svc ConfigService) ListRegistryCredentials(namespace string) ([]model_config.RegistryCredentialInfo, error) {
+	secrets, err := svc.repository.Kubernetes.ListRegistrySecrets(namespace)
+	if err != nil {
+		return nil, err
+	}
+	var result = []model_config.RegistryCredentialInfo{}
+	for _, secret := range secrets {
+		result = append(result, model_config.RegistryCredentialInfo{
+			Name:      secret.Name,
+			Server:    secret.Annotations[adapter.RegistryServerAnnotation],
+			Username:  adapter.RegistryUsername(secret),
+			CreatedAt: secret.CreationTimestamp.Time,
+		})
+	}
+	return result, nil
+}
+
+func (svc ConfigService) DeleteRegistryCredential(namespace, name string) error {
+	// Pull secrets are set on the pod, the containers have none of their own
+	count, err := svc.repository.MongoDB.CountDocuments("DEPLOYMENTS", bson.M{"namespace": namespace, "image_pull_secrets": name})
+	if err != nil {
+		return fmt.Errorf("failed to check deployments referencing %s: %w", name, err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%s is still referenced by %d deployment(s)", name, count)
+	}
+	return svc.repository.Kubernetes.DeleteRegistrySecret(namespace, name)
+}
+
+// ResolveImagePullSecrets returns the registry secrets a deployment pulls its images with.
+// Without a selection every registry credential of the tenant is used, a selection must only name existing ones.
+func (svc ConfigService) ResolveImagePullSecrets(namespace string, selected []string) ([]string, error) {
+	secrets, err := svc.repository.Kubernetes.ListRegistrySecrets(namespace)
+	if err != nil {
+		return nil, err
+	}
+	available := map[string]bool{}
+	names := []string{}
+	for _, secret := range secrets {
+		available[secret.Name] = true
+		names = append(names, secret.Name)
+	}
+	if selected == nil {
+		return names, nil
+	}
+	for _, name := range selected {
+		if !available[name] {
+			return nil, fmt.Errorf("registry credential %s not found", name)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,139 @@
+package svc
+
+import (
+	model_deployment "deployment-service/models/model.deployment"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func autoscalerName(deploymentName string) string {
+	return deploymentName + "-hpa"
+}
+
+// applyAutoscaler creates or updates the HPA and persists its config on the DEPLOYMENTS document
+func (svc DeploymentService) applyAutoscaler(namespace, deploymentName string, config model_deployment.AutoscalerConfig) (map[string]interface{}, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	err := svc.repository.Kubernetes.ApplyHorizontalPodAutoscaler(namespace, autoscalerName(deploymentName), deploymentName,
+		config.MinReplicas, config.MaxReplicas, config.TargetCPUUtilization, config.TargetMemoryUtilization)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"autoscaler": config})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	resp["autoscaler"] = config
+	return resp, nil
+}
+
+// CreateAutoscaler enables autoscaling for a deployment
+func (svc DeploymentService) CreateAutoscaler(namespace, deploymentName string, config model_deployment.AutoscalerConfig,
+	actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_AUTOSCALE, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if err := deployment.CheckChangeable("autoscale"); err != nil {
+		return nil, err
+	}
+	if deployment.Autoscaler != nil {
+		return nil, fmt.Errorf("deployment %s already has an autoscaler", deploymentName)
+	}
+	return svc.applyAutoscaler(namespace, deploymentName, config)
+}
+
+// UpdateAutoscaler changes the replica bounds or targets of a deployment's autoscaler
+func (svc DeploymentService) UpdateAutoscaler(namespace, deploymentName string, config model_deployment.AutoscalerConfig,
+	actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_AUTOSCALE, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if err := deployment.CheckChangeable("autoscale"); err != nil {
+		return nil, err
+	}
+	if deployment.Autoscaler == nil {
+		return nil, fmt.Errorf("deployment %s has no autoscaler", deploymentName)
+	}
+	return svc.applyAutoscaler(namespace, deploymentName, config)
+}
+
+// GetAutoscaler returns the autoscaler config of a deployment together with the live HPA status
+func (svc DeploymentService) GetAutoscaler(namespace, deploymentName string) (*model_deployment.AutoscalerStatus, error) {
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if deployment.Autoscaler == nil {
+		return nil, fmt.Errorf("deployment %s has no autoscaler", deploymentName)
+	}
+	hpa, err := svc.repository.Kubernetes.GetHorizontalPodAutoscaler(namespace, autoscalerName(deploymentName))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &model_deployment.AutoscalerStatus{
+		Config:          *deployment.Autoscaler,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+	}
+	if hpa.Status.LastScaleTime != nil {
+		lastScaleTime := hpa.Status.LastScaleTime.Time
+		status.LastScaleTime = &lastScaleTime
+	}
+	for _, metric := range hpa.Status.CurrentMetrics {
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			continue
+		}
+		switch metric.Resource.Name {
+		case corev1.ResourceCPU:
+			status.CurrentCPUUtilization = metric.Resource.Current.AverageUtilization
+		case corev1.ResourceMemory:
+			status.CurrentMemoryUtilization = metric.Resource.Current.AverageUtilization
+		}
+	}
+	return status, nil
+}
+
+// DeleteAutoscaler removes the HPA, the deployment keeps the replica count the HPA last set
+func (svc DeploymentService) DeleteAutoscaler(namespace, deploymentName string, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_AUTOSCALE, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if deployment.Autoscaler == nil {
+		return nil, errors.New("deployment has no autoscaler")
+	}
+	if err := svc.repository.Kubernetes.DeleteHorizontalPodAutoscaler(namespace, autoscalerName(deploymentName)); err != nil {
+		return nil, err
+	}
+
+	// Persist the replica count the HPA left behind so later updates start from it
+	fields := bson.M{"updatedAt": time.Now()}
+	if kubernetesManifest, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, deploymentName); err == nil {
+		fields["replicas"] = kubernetesManifest.DesiredReplicas
+	}
+	res, err := svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", bson.M{"namespace": namespace, "name": deploymentName}, bson.M{
+		"$set":   fields,
+		"$unset": bson.M{"autoscaler": ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	return map[string]interface{}{
+		"result": res,
+	}, nil
+}
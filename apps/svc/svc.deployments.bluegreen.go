@@ -0,0 +1,314 @@
+package svc
+
+import (
+	"context"
+	adapter "deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// blue/green pods carry the color label next to the app label, the service selects one color after the first switch
+const (
+	colorLabel = "color"
+	colorBlue  = "blue"
+	colorGreen = "green"
+)
+
+func otherColor(color string) string {
+	if color == colorBlue {
+		return colorGreen
+	}
+	return colorBlue
+}
+
+func blueGreenName(deploymentName, color string) string {
+	return deploymentName + "-" + color
+}
+
+func colorSelector(deploymentName, color string) map[string]string {
+	return map[string]string{"app": deploymentName, colorLabel: color}
+}
+
+// startBlueGreen runs the new image of an update as a parallel deployment of the other color.
+// The blue/green worker switches the service over once it is fully available.
+func (svc DeploymentService) startBlueGreen(namespace string, deployment *model_deployment.CreateDeploymentRequest,
+	payload *model_deployment.UpdateDeploymentReq) (map[string]interface{}, error) {
+	if payload.Image == "" || payload.Image == deployment.Image {
+		return nil, errors.New("a blue/green release needs a new image")
+	}
+	if payload.Container != "" && payload.Container != deployment.Name {
+		return nil, errors.New("a blue/green release can only change the image of the main container")
+	}
+	if deployment.BlueGreen != nil {
+		return nil, fmt.Errorf("deployment %s already has a blue/green release in phase %s", deployment.Name, deployment.BlueGreen.Phase)
+	}
+	if deployment.Canary != nil {
+		return nil, fmt.Errorf("deployment %s has a canary running %s, promote or abort it first", deployment.Name, deployment.Canary.Image)
+	}
+	retention := payload.BlueGreen.RetentionSeconds
+	if retention == 0 {
+		retention = int64(constants.BLUE_GREEN_RETENTION_SECONDS)
+	}
+
+	// The stable pods need a color to switch back to, deployments without one become blue.
+	// Relabelling rolls the pods, the service selects them by app label until it is pinned to their color.
+	stableColor := colorBlue
+	relabelled := false
+	generation, err := svc.repository.Kubernetes.UpdateDeploymentSpec(namespace, deployment.Name, func(d *appsv1.Deployment) error {
+		if color, ok := d.Spec.Template.Labels[colorLabel]; ok {
+			stableColor = color
+			return nil
+		}
+		d.Spec.Template.Labels[colorLabel] = stableColor
+		relabelled = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to label deployment %s: %w", deployment.Name, err)
+	}
+
+	newColor := otherColor(stableColor)
+	blueGreen := model_deployment.BlueGreenState{
+		Phase:            model_deployment.BLUE_GREEN_PENDING,
+		StableColor:      stableColor,
+		NewColor:         newColor,
+		Image:            payload.Image,
+		PreviousImage:    deployment.Image,
+		RetentionSeconds: retention,
+		StartedAt:        time.Now(),
+	}
+	// On the first release the new color could only start next to the stable pods once the service no longer
+	// selects by app label alone, the blue/green worker creates it when the relabelled pods are rolled out
+	if relabelled {
+		blueGreen.Phase = model_deployment.BLUE_GREEN_PREPARING
+		blueGreen.Generation = generation
+	} else if err := svc.createNewColor(namespace, deployment.Name, &blueGreen); err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.updateDeploymentInMongoDB(namespace, deployment.Name, bson.M{"blue_green": blueGreen})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deployment.Name, err)
+	}
+	resp["blue_green"] = blueGreen
+	return resp, nil
+}
+
+// createNewColor pins the service to the stable color and starts the new color next to it
+func (svc DeploymentService) createNewColor(namespace, deploymentName string, blueGreen *model_deployment.BlueGreenState) error {
+	err := svc.repository.Kubernetes.SetServiceSelector(namespace, deploymentName+"-service", colorSelector(deploymentName, blueGreen.StableColor))
+	if err != nil {
+		return err
+	}
+	kubernetesManifest, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, deploymentName)
+	if err != nil {
+		return err
+	}
+	err = svc.repository.Kubernetes.CloneDeployment(namespace, deploymentName, blueGreenName(deploymentName, blueGreen.NewColor),
+		map[string]string{colorLabel: blueGreen.NewColor}, kubernetesManifest.DesiredReplicas, blueGreen.Image)
+	if err != nil {
+		return fmt.Errorf("failed to create %s deployment: %w", blueGreen.NewColor, err)
+	}
+	return nil
+}
+
+// GetBlueGreen reports the blue/green release of a deployment
+func (svc DeploymentService) GetBlueGreen(namespace, deploymentName string) (*model_deployment.BlueGreenState, error) {
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if deployment.BlueGreen == nil {
+		return nil, fmt.Errorf("deployment %s has no blue/green release", deploymentName)
+	}
+	return deployment.BlueGreen, nil
+}
+
+// SwitchBackBlueGreen points the service back at the stable color and removes the new one.
+// It is only possible until the retention window is over.
+func (svc DeploymentService) SwitchBackBlueGreen(namespace, deploymentName string) (map[string]interface{}, error) {
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	blueGreen := deployment.BlueGreen
+	if blueGreen == nil || blueGreen.Phase == model_deployment.BLUE_GREEN_RETIRING {
+		return nil, fmt.Errorf("deployment %s has no blue/green release to switch back", deploymentName)
+	}
+	if blueGreen.Phase == model_deployment.BLUE_GREEN_SWITCHED {
+		err = svc.repository.Kubernetes.SetServiceSelector(namespace, deploymentName+"-service", colorSelector(deploymentName, blueGreen.StableColor))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return svc.endBlueGreen(namespace, deploymentName, blueGreen, bson.M{})
+}
+
+// endBlueGreen removes the parallel deployment and the blue/green state, fields are set along
+func (svc DeploymentService) endBlueGreen(namespace, deploymentName string, blueGreen *model_deployment.BlueGreenState, fields bson.M) (map[string]interface{}, error) {
+	if err := svc.repository.Kubernetes.DeleteDeployment(namespace, blueGreenName(deploymentName, blueGreen.NewColor)); err != nil {
+		return nil, fmt.Errorf("failed to delete %s deployment: %w", blueGreen.NewColor, err)
+	}
+	fields["updatedAt"] = time.Now()
+	res, err := svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", bson.M{"namespace": namespace, "name": deploymentName}, bson.M{
+		"$set":   fields,
+		"$unset": bson.M{"blue_green": ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	return map[string]interface{}{
+		"result": res,
+	}, nil
+}
+
+// advanceBlueGreen moves a blue/green release to its next phase once the cluster is ready for it
+func (svc DeploymentService) advanceBlueGreen(deployment *model_deployment.CreateDeploymentRequest) error {
+	namespace, deploymentName, blueGreen := deployment.Namespace, deployment.Name, deployment.BlueGreen
+	stable, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, deploymentName)
+	if err != nil {
+		return err
+	}
+
+	switch blueGreen.Phase {
+	case model_deployment.BLUE_GREEN_PREPARING:
+		if stable.ObservedGeneration < blueGreen.Generation {
+			return nil
+		}
+		result, message := stable.RolloutResult()
+		if result == constants.ROLLOUT_FAILED || result == constants.ROLLOUT_TIMED_OUT {
+			logger.Logger.Warn("Blue/green release failed while labelling the stable pods",
+				zap.String("deployment", deploymentName), zap.String("message", message))
+			_, err := svc.endBlueGreen(namespace, deploymentName, blueGreen, bson.M{})
+			return err
+		}
+		if result != constants.ROLLOUT_SUCCESS {
+			return nil
+		}
+		if err := svc.createNewColor(namespace, deploymentName, blueGreen); err != nil {
+			return err
+		}
+		_, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"blue_green.phase": model_deployment.BLUE_GREEN_PENDING})
+		return err
+
+	case model_deployment.BLUE_GREEN_PENDING:
+		parallel, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, blueGreenName(deploymentName, blueGreen.NewColor))
+		if err != nil {
+			return err
+		}
+		result, message := parallel.RolloutResult()
+		if result == constants.ROLLOUT_FAILED || result == constants.ROLLOUT_TIMED_OUT {
+			logger.Logger.Warn("Blue/green release failed, removing the new color",
+				zap.String("deployment", deploymentName), zap.String("message", message))
+			_, err := svc.endBlueGreen(namespace, deploymentName, blueGreen, bson.M{})
+			return err
+		}
+		// Switch once the new color is fully available
+		if result != constants.ROLLOUT_SUCCESS {
+			return nil
+		}
+		err = svc.repository.Kubernetes.SetServiceSelector(namespace, deploymentName+"-service", colorSelector(deploymentName, blueGreen.NewColor))
+		if err != nil {
+			return err
+		}
+		switchedAt := time.Now()
+		retainUntil := switchedAt.Add(time.Duration(blueGreen.RetentionSeconds) * time.Second)
+		_, err = svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{
+			"blue_green.phase":       model_deployment.BLUE_GREEN_SWITCHED,
+			"blue_green.switchedAt":  switchedAt,
+			"blue_green.retainUntil": retainUntil,
+		})
+		return err
+
+	case model_deployment.BLUE_GREEN_SWITCHED:
+		if blueGreen.RetainUntil != nil && time.Now().Before(*blueGreen.RetainUntil) {
+			return nil
+		}
+		// Roll the deployment itself to the new color, the service keeps selecting only new color pods
+		generation, err := svc.repository.Kubernetes.UpdateDeploymentSpec(namespace, deploymentName, func(d *appsv1.Deployment) error {
+			d.Spec.Template.Labels[colorLabel] = blueGreen.NewColor
+			adapter.MainContainer(&d.Spec.Template.Spec, deploymentName).Image = blueGreen.Image
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		_, err = svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{
+			"blue_green.phase":      model_deployment.BLUE_GREEN_RETIRING,
+			"blue_green.generation": generation,
+		})
+		return err
+
+	case model_deployment.BLUE_GREEN_RETIRING:
+		if result, _ := stable.RolloutResult(); result != constants.ROLLOUT_SUCCESS || stable.ObservedGeneration < blueGreen.Generation {
+			return nil
+		}
+		history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_PROMOTE, model_deployment.Actor{Name: model_deployment.ACTOR_RELEASE_WORKER})
+		_, err := svc.endBlueGreen(namespace, deploymentName, blueGreen, bson.M{
+			"image":      blueGreen.Image,
+			"generation": stable.Generation,
+			"revision":   stable.Revision,
+		})
+		svc.endHistory(history, err)
+		return err
+	}
+	return nil
+}
+
+// RunReleaseWorker advances the blue/green releases and canary promotions of all tenants until ctx is cancelled
+func (svc DeploymentService) RunReleaseWorker(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(constants.BLUE_GREEN_CHECK_INTERVAL_SECONDS) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// Releases are advanced by one replica at a time
+		release, ok := svc.claimLease("release-worker", time.Duration(constants.WORKER_LEASE_SECONDS)*time.Second)
+		if !ok {
+			continue
+		}
+		svc.advanceReleases(ctx)
+		release(time.Now())
+	}
+}
+
+// advanceReleases moves every blue/green release and canary promotion on to its next phase once it can
+func (svc DeploymentService) advanceReleases(ctx context.Context) {
+	cursor, err := svc.repository.MongoDB.FindMany("DEPLOYMENTS", bson.M{"$or": bson.A{
+		bson.M{"blue_green": bson.M{"$exists": true}},
+		bson.M{"canary.phase": model_deployment.CANARY_PROMOTING},
+	}})
+	if err != nil {
+		logger.Logger.Error("Error while fetching releasing deployments", zap.Any(logger.KEY_ERROR, err.Error()))
+		return
+	}
+	var deployments []model_deployment.CreateDeploymentRequest
+	if err := cursor.All(ctx, &deployments); err != nil {
+		logger.Logger.Error("Error while decoding releasing deployments", zap.Any(logger.KEY_ERROR, err.Error()))
+		return
+	}
+	for i := range deployments {
+		if deployments[i].Canary != nil {
+			if err := svc.advanceCanary(&deployments[i]); err != nil {
+				logger.Logger.Error("Error while advancing canary promotion", zap.String("deployment", deployments[i].Name),
+					zap.String("namespace", deployments[i].Namespace), zap.Any(logger.KEY_ERROR, err.Error()))
+			}
+			continue
+		}
+		if err := svc.advanceBlueGreen(&deployments[i]); err != nil {
+			logger.Logger.Error("Error while advancing blue/green release", zap.String("deployment", deployments[i].Name),
+				zap.String("namespace", deployments[i].Namespace), zap.Any(logger.KEY_ERROR, err.Error()))
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package svc
+
+import (
+	adapter "deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// canary pods carry the track label next to the app label the service selects on
+const (
+	trackLabel  = "track"
+	trackCanary = "canary"
+)
+
+func canaryName(deploymentName string) string {
+	return deploymentName + "-canary"
+}
+
+// canaryWeight is the canary's share of the pods in percent
+func canaryWeight(stableReplicas, canaryReplicas int32) int32 {
+	if stableReplicas+canaryReplicas == 0 {
+		return 0
+	}
+	return canaryReplicas * 100 / (stableReplicas + canaryReplicas)
+}
+
+// startCanary runs the new image of an update in a second deployment behind the same service.
+// Traffic splits by the ratio of canary to stable replicas.
+func (svc DeploymentService) startCanary(namespace string, deployment *model_deployment.CreateDeploymentRequest,
+	payload *model_deployment.UpdateDeploymentReq) (map[string]interface{}, error) {
+	if payload.Image == "" || payload.Image == deployment.Image {
+		return nil, errors.New("a canary release needs a new image")
+	}
+	if payload.Container != "" && payload.Container != deployment.Name {
+		return nil, errors.New("a canary release can only change the image of the main container")
+	}
+	if deployment.Canary != nil {
+		return nil, fmt.Errorf("deployment %s already has a canary running %s, promote or abort it first", deployment.Name, deployment.Canary.Image)
+	}
+	if deployment.BlueGreen != nil {
+		return nil, fmt.Errorf("deployment %s has a blue/green release in phase %s", deployment.Name, deployment.BlueGreen.Phase)
+	}
+	replicas := payload.Canary.Replicas
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	err := svc.repository.Kubernetes.CloneDeployment(namespace, deployment.Name, canaryName(deployment.Name),
+		map[string]string{trackLabel: trackCanary}, replicas, payload.Image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create canary: %w", err)
+	}
+
+	canary := model_deployment.CanaryState{
+		Phase:     model_deployment.CANARY_RUNNING,
+		Image:     payload.Image,
+		Replicas:  replicas,
+		StartedAt: time.Now(),
+	}
+	resp, err := svc.updateDeploymentInMongoDB(namespace, deployment.Name, bson.M{"canary": canary})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deployment.Name, err)
+	}
+	resp["canary"] = canary
+	resp["weight"] = canaryWeight(deployment.Replicas, replicas)
+	return resp, nil
+}
+
+// GetCanary reports the canary of a deployment next to its stable version
+func (svc DeploymentService) GetCanary(namespace, deploymentName string) (*model_deployment.CanaryStatus, error) {
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if deployment.Canary == nil {
+		return nil, fmt.Errorf("deployment %s has no canary", deploymentName)
+	}
+	stable, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+	canary, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, canaryName(deploymentName))
+	if err != nil {
+		return nil, err
+	}
+	return &model_deployment.CanaryStatus{
+		CanaryState:         *deployment.Canary,
+		StableImage:         stable.Image,
+		StableReadyReplicas: stable.ReadyReplicas,
+		CanaryReadyReplicas: canary.ReadyReplicas,
+		Weight:              canaryWeight(stable.ReadyReplicas, canary.ReadyReplicas),
+	}, nil
+}
+
+// PromoteCanary rolls the canary image out to the stable deployment. The canary keeps serving until
+// the release worker has seen the stable rollout succeed, then the record takes the canary image and the canary is removed.
+func (svc DeploymentService) PromoteCanary(namespace, deploymentName string, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_PROMOTE, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if deployment.Canary == nil {
+		return nil, fmt.Errorf("deployment %s has no canary", deploymentName)
+	}
+	if deployment.Canary.Phase == model_deployment.CANARY_PROMOTING {
+		return nil, fmt.Errorf("the canary of deployment %s is already being promoted", deploymentName)
+	}
+	image := deployment.Canary.Image
+	// The record keeps the stable image until the rollout succeeded, the history has the image being rolled out
+	history.record.Changes = []model_deployment.HistoryChange{{Field: "image", Before: deployment.Image, After: image}}
+
+	generation, err := svc.setStableImage(namespace, deploymentName, image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to promote canary: %w", err)
+	}
+	revision, err := svc.repository.Kubernetes.WaitForDeploymentRevision(namespace, deploymentName, generation,
+		time.Duration(constants.ROLLOUT_REVISION_WAIT_SECONDS)*time.Second)
+	if err != nil {
+		logger.Logger.Warn("Error while waiting for deployment revision", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+
+	res, err := svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", bson.M{"namespace": namespace, "name": deploymentName}, bson.M{
+		"$set": bson.M{
+			"generation":          generation,
+			"revision":            revision,
+			"canary.phase":        model_deployment.CANARY_PROMOTING,
+			"canary.generation":   generation,
+			"canary.stable_image": deployment.Image,
+			"updatedAt":           time.Now(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	return map[string]interface{}{
+		"result":     res,
+		"image":      image,
+		"generation": generation,
+		"revision":   revision,
+		"phase":      model_deployment.CANARY_PROMOTING,
+	}, nil
+}
+
+// setStableImage sets the image of the main container of the stable deployment and returns its new generation
+func (svc DeploymentService) setStableImage(namespace, deploymentName, image string) (int64, error) {
+	return svc.repository.Kubernetes.UpdateDeploymentSpec(namespace, deploymentName, func(d *appsv1.Deployment) error {
+		adapter.MainContainer(&d.Spec.Template.Spec, deploymentName).Image = image
+		return nil
+	})
+}
+
+// advanceCanary completes a promotion once the stable deployment has rolled out the canary image:
+// the record takes the canary image and the canary is removed
+func (svc DeploymentService) advanceCanary(deployment *model_deployment.CreateDeploymentRequest) error {
+	namespace, deploymentName, canary := deployment.Namespace, deployment.Name, deployment.Canary
+	if canary.Phase != model_deployment.CANARY_PROMOTING {
+		return nil
+	}
+	stable, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, deploymentName)
+	if err != nil {
+		return err
+	}
+	if stable.ObservedGeneration < canary.Generation {
+		return nil
+	}
+	result, message := stable.RolloutResult()
+	if result == constants.ROLLOUT_FAILED || result == constants.ROLLOUT_TIMED_OUT {
+		logger.Logger.Warn("Canary promotion failed, keeping the canary",
+			zap.String("deployment", deploymentName), zap.String("message", message))
+		_, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"canary.phase": model_deployment.CANARY_PROMOTION_FAILED})
+		return err
+	}
+	if result != constants.ROLLOUT_SUCCESS {
+		return nil
+	}
+	if err := svc.repository.Kubernetes.DeleteDeployment(namespace, canaryName(deploymentName)); err != nil {
+		return fmt.Errorf("failed to delete canary: %w", err)
+	}
+	_, err = svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", bson.M{"namespace": namespace, "name": deploymentName}, bson.M{
+		"$set":   bson.M{"image": canary.Image, "updatedAt": time.Now()},
+		"$unset": bson.M{"canary": ""},
+	})
+	return err
+}
+
+// AbortCanary removes the canary, the stable deployment keeps serving all traffic. A canary whose promotion
+// failed first rolls the stable deployment back to its image from before the promotion.
+func (svc DeploymentService) AbortCanary(namespace, deploymentName string) (map[string]interface{}, error) {
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if deployment.Canary == nil {
+		return nil, fmt.Errorf("deployment %s has no canary", deploymentName)
+	}
+	if deployment.Canary.Phase == model_deployment.CANARY_PROMOTING {
+		return nil, fmt.Errorf("the canary of deployment %s is being promoted, wait for the promotion to finish or fail", deploymentName)
+	}
+	set := bson.M{"updatedAt": time.Now()}
+	if deployment.Canary.Phase == model_deployment.CANARY_PROMOTION_FAILED {
+		// Promotions started before the stable image was kept have none, the record's image is the closest
+		image := deployment.Canary.StableImage
+		if image == "" {
+			image = deployment.Image
+		}
+		generation, err := svc.setStableImage(namespace, deploymentName, image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to roll back deployment %s to %s: %w", deploymentName, image, err)
+		}
+		revision, err := svc.repository.Kubernetes.WaitForDeploymentRevision(namespace, deploymentName, generation,
+			time.Duration(constants.ROLLOUT_REVISION_WAIT_SECONDS)*time.Second)
+		if err != nil {
+			logger.Logger.Warn("Error while waiting for deployment revision", zap.Any(logger.KEY_ERROR, err.Error()))
+		}
+		set["image"], set["generation"], set["revision"] = image, generation, revision
+	}
+	if err := svc.repository.Kubernetes.DeleteDeployment(namespace, canaryName(deploymentName)); err != nil {
+		return nil, fmt.Errorf("failed to delete canary: %w", err)
+	}
+
+	res, err := svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", bson.M{"namespace": namespace, "name": deploymentName}, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"canary": ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	return map[string]interface{}{
+		"result": res,
+	}, nil
+}
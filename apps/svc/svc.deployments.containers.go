@@ -0,0 +1,65 @@
+package svc
+
+import (
+	adapter "deployment-service/apps/repository/adapter"
+	model_deployment "deployment-service/models/model.deployment"
+	"fmt"
+)
+
+// containerInfos lists the main container, the sidecars and the init containers of a deployment
+func containerInfos(kubernetesManifest *adapter.KubernetesManifest) []model_deployment.ContainerInfo {
+	var containers = []model_deployment.ContainerInfo{}
+	for _, container := range kubernetesManifest.Containers {
+		containers = append(containers, model_deployment.ContainerInfo{Name: container.Name, Image: container.Image})
+	}
+	for _, container := range kubernetesManifest.InitContainers {
+		containers = append(containers, model_deployment.ContainerInfo{Name: container.Name, Image: container.Image, Init: true})
+	}
+	return containers
+}
+
+// updateContainer applies the image, resources, env and probes of an update to the named sidecar or init container.
+// It returns the updated copies of the deployment's sidecars and init containers.
+func (svc DeploymentService) updateContainer(namespace string, deployment *model_deployment.CreateDeploymentRequest,
+	payload *model_deployment.UpdateDeploymentReq) ([]model_deployment.Container, []model_deployment.Container, error) {
+	sidecars := append([]model_deployment.Container{}, deployment.Sidecars...)
+	initContainers := append([]model_deployment.Container{}, deployment.InitContainers...)
+
+	containers, init := sidecars, false
+	i := model_deployment.FindContainer(sidecars, payload.Container)
+	if i == -1 {
+		containers, init = initContainers, true
+		i = model_deployment.FindContainer(initContainers, payload.Container)
+	}
+	if i == -1 {
+		return nil, nil, fmt.Errorf("container %s not found in deployment %s", payload.Container, deployment.Name)
+	}
+
+	container := containers[i]
+	if payload.Image != "" {
+		container.Image = payload.Image
+	}
+	if payload.Resources != nil {
+		current := model_deployment.DeploymentResources{}
+		if container.Resources != nil {
+			current = *container.Resources
+		}
+		resources := payload.Resources.Merge(current)
+		container.Resources = &resources
+	}
+	if payload.Env != nil {
+		if err := (ConfigService{svc.repository}).ValidateEnvReferences(namespace, payload.Env); err != nil {
+			return nil, nil, err
+		}
+		container.Env = payload.Env
+	}
+	if payload.Probes != nil {
+		probes := *payload.Probes
+		container.Probes = &probes
+	}
+	if err := container.Validate(init); err != nil {
+		return nil, nil, &ValidationError{Field: "container " + container.Name, Err: err}
+	}
+	containers[i] = container
+	return sidecars, initContainers, nil
+}
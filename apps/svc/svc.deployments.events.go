@@ -0,0 +1,171 @@
+package svc
+
+import (
+	"deployment-service/constants"
+	model_deployment "deployment-service/models/model.deployment"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetLatestEvents returns a page of the namespace events, newest first, filtered by deployment, type and time
+func (svc DeploymentService) GetLatestEvents(namespace string, query model_deployment.EventsQuery) (*model_deployment.EventsPage, error) {
+	fieldSelector := ""
+	if query.Type != "" {
+		fieldSelector = "type=" + query.Type
+	}
+	events, err := svc.repository.Kubernetes.ListEvents(namespace, fieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var since time.Time
+	if query.Since != "" {
+		if since, err = time.Parse(time.RFC3339, query.Since); err != nil {
+			return nil, fmt.Errorf("invalid since %q, expected RFC3339: %w", query.Since, err)
+		}
+	}
+	matches := func(event corev1.Event) bool { return true }
+	if query.Deployment != "" {
+		if matches, err = svc.deploymentEventFilter(namespace, query.Deployment); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []model_deployment.Event
+	for _, event := range events {
+		mapped := toEvent(event)
+		if !since.IsZero() && mapped.LastTimestamp.Before(since) {
+			continue
+		}
+		if !matches(event) {
+			continue
+		}
+		result = append(result, mapped)
+	}
+
+	// Newest first, the name breaks ties so the cursor position is stable
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].LastTimestamp.Equal(result[j].LastTimestamp) {
+			return result[i].LastTimestamp.After(result[j].LastTimestamp)
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	if query.Cursor != "" {
+		cursorTime, cursorName, err := decodeEventCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		start := sort.Search(len(result), func(i int) bool {
+			if !result[i].LastTimestamp.Equal(cursorTime) {
+				return result[i].LastTimestamp.Before(cursorTime)
+			}
+			return result[i].Name > cursorName
+		})
+		result = result[start:]
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = constants.EVENTS_DEFAULT_LIMIT
+	}
+	if limit > constants.EVENTS_MAX_LIMIT {
+		limit = constants.EVENTS_MAX_LIMIT
+	}
+	page := &model_deployment.EventsPage{Events: []model_deployment.Event{}}
+	if len(result) > limit {
+		last := result[limit-1]
+		page.NextCursor = encodeEventCursor(last.LastTimestamp, last.Name)
+		result = result[:limit]
+	}
+	page.Events = append(page.Events, result...)
+	return page, nil
+}
+
+// deploymentEventFilter matches the events of a deployment, its ReplicaSets and their pods
+func (svc DeploymentService) deploymentEventFilter(namespace, deploymentName string) (func(corev1.Event) bool, error) {
+	replicaSetNames, err := svc.repository.Kubernetes.GetDeploymentReplicaSetNames(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+	return func(event corev1.Event) bool {
+		object := event.InvolvedObject
+		switch object.Kind {
+		case "Deployment":
+			return object.Name == deploymentName
+		case "ReplicaSet":
+			for _, name := range replicaSetNames {
+				if object.Name == name {
+					return true
+				}
+			}
+		case "Pod":
+			// Pods are named after their ReplicaSet, this also matches pods that are already gone
+			for _, name := range replicaSetNames {
+				if strings.HasPrefix(object.Name, name+"-") {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
+
+// toEvent maps a kubernetes event, falling back to the event time for events without timestamps
+func toEvent(event corev1.Event) model_deployment.Event {
+	lastTimestamp := event.LastTimestamp.Time
+	if lastTimestamp.IsZero() {
+		lastTimestamp = event.EventTime.Time
+	}
+	if lastTimestamp.IsZero() {
+		lastTimestamp = event.CreationTimestamp.Time
+	}
+	firstTimestamp := event.FirstTimestamp.Time
+	if firstTimestamp.IsZero() {
+		firstTimestamp = lastTimestamp
+	}
+	count := event.Count
+	if count == 0 {
+		count = 1
+	}
+	return model_deployment.Event{
+		Name: event.Name,
+		InvolvedObject: model_deployment.EventObject{
+			Kind: event.InvolvedObject.Kind,
+			Name: event.InvolvedObject.Name,
+		},
+		Type:           event.Type,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Count:          count,
+		FirstTimestamp: firstTimestamp,
+		LastTimestamp:  lastTimestamp,
+	}
+}
+
+func encodeEventCursor(lastTimestamp time.Time, name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastTimestamp.UnixNano(), 10) + ":" + name))
+}
+
+func decodeEventCursor(cursor string) (time.Time, string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+	return time.Unix(0, nanos), parts[1], nil
+}
@@ -3,17 +3,23 @@ package svc
 import (
 	"context"
 	adapter "deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
 	"deployment-service/logger"
 	model_build "deployment-service/models/model.build"
 	model_deployment "deployment-service/models/model.deployment"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type DeploymentService struct {
@@ -24,6 +30,12 @@ func (svc DeploymentService) GetDeploymentsByNamespace(namespace string) ([]map[
 	deployments, err := svc.repository.Kubernetes.ListDeployments(namespace)
 	var deploymentInfo []map[string]interface{}
 	if err == nil {
+		// Live usage is fetched once for the namespace and split per deployment
+		pods, podsErr := svc.repository.Kubernetes.ListPods(namespace)
+		if podsErr != nil {
+			logger.Logger.Warn("Error while listing pods", zap.Any(logger.KEY_ERROR, podsErr.Error()))
+		}
+		usage := podsUsage(svc.repository, namespace, "")
 
 		for _, d := range deployments {
 			replicasNeeded := int32(0)
@@ -38,9 +50,8 @@ func (svc DeploymentService) GetDeploymentsByNamespace(namespace string) ([]map[
 			// Calculate the age of the deployment
 			age := time.Since(d.CreationTimestamp.Time)
 
-			// Fetch resource utilization if you have metrics access (optional, requires Metrics API)
-			// For example, get memory and CPU usage per pod (implement separately)
-			// resourceUtilization := getDeploymentResourceUtilization(d.Name, namespace, k)
+			// Resource utilization of the deployment's pods, left out when the metrics api is unavailable
+			utilization := resourceUtilization(svc.repository.Metrics.Source(), selectedPods(d, pods), usage)
 
 			// Create YAML manifest string
 			// deploymentYAML, err := yaml.Marshal(d)
@@ -50,14 +61,14 @@ func (svc DeploymentService) GetDeploymentsByNamespace(namespace string) ([]map[
 			// }
 
 			deploymentDetails := map[string]interface{}{
-				"Deployment Name":    d.Name,
-				"Namespace":          d.Namespace,
-				"Replicas Needed":    replicasNeeded,
-				"Replicas Available": replicasAvailable,
-				"Status":             d.Status.Conditions,
-				"Age":                age.String(),
-				"Creation Time":      d.CreationTimestamp.Time,
-				// "Resource Utilization": resourceUtilization, // CPU/Memory data as needed
+				"Deployment Name":      d.Name,
+				"Namespace":            d.Namespace,
+				"Replicas Needed":      replicasNeeded,
+				"Replicas Available":   replicasAvailable,
+				"Status":               d.Status.Conditions,
+				"Age":                  age.String(),
+				"Creation Time":        d.CreationTimestamp.Time,
+				"Resource Utilization": utilization,
 				// "Deployment Manifest": string(deploymentYAML),
 			}
 
@@ -72,9 +83,9 @@ func (svc DeploymentService) GetDeploymentsByNamespace(namespace string) ([]map[
 func (svc DeploymentService) GetTenantKubernetesInfo(namespace string) (model_build.TenantResourceResp, error) {
 	var resp = model_build.TenantResourceResp{}
 	// Create Namespace if not exists
-	nserr := svc.repository.Kubernetes.CreateNamespaceIfNotExists(namespace)
+	nserr := svc.CreateNamespaceIfNotExists(namespace)
 	if nserr != nil {
-		fmt.Println("Error creating namespace %s: %v", namespace, nserr)
+		fmt.Printf("Error creating namespace %s: %v\n", namespace, nserr)
 		return resp, nserr
 	}
 	// Fetch Pods
@@ -101,11 +112,22 @@ func (svc DeploymentService) GetTenantKubernetesInfo(namespace string) (model_bu
 		return resp, err
 	}
 
+	// Fetch the quota usage, tenants created before quotas were applied have none
+	quota, err := (TenantService{svc.repository}).GetTenantQuotaUsage(namespace)
+	if err != nil {
+		logger.Logger.Warn("Error while fetching tenant quota", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+
+	// Live usage of all pods in the tenant namespace
+	usage := podsUsage(svc.repository, namespace, "")
+
 	resp.KubernetesVersion = k8sVersion
 	resp.NoOfDeployments = int64(len(deployments))
 	resp.NoOfPods = int64(len(pods))
 	resp.NoOfServices = int64(len(services))
 	resp.TenantUsername = namespace
+	resp.Quota = quota
+	resp.Usage = resourceUtilization(svc.repository.Metrics.Source(), pods, usage)
 	return resp, nil
 }
 
@@ -125,6 +147,12 @@ func (svc DeploymentService) GetDeploymentByName(namespace, deploymentName strin
 	if err != nil {
 		svcInfo = "UNDEFINED"
 	}
+	// Live usage of the deployment's pods
+	var utilization *model_deployment.ResourceUtilization
+	if pods, err := svc.repository.Kubernetes.GetDeploymentPods(namespace, deploymentName); err == nil {
+		usage := podsUsage(svc.repository, namespace, "app="+deploymentName)
+		utilization = resourceUtilization(svc.repository.Metrics.Source(), pods, usage)
+	}
 	// Populate DeploymentInfo struct
 	deploymentInfo := &model_deployment.DeploymentInfo{
 		DeploymentName:    deploymentName,
@@ -134,6 +162,10 @@ func (svc DeploymentService) GetDeploymentByName(namespace, deploymentName strin
 		CurrentReplicas:   currentReplicas,
 		Image:             kubernetesManifest.Image,
 		AvailableReplicas: availableReplicas, // Add available replicas field
+		Env:               model_deployment.EnvFromEnvVars(kubernetesManifest.MainContainer.Env),
+		Probes:            model_deployment.ProbesFromContainer(kubernetesManifest.MainContainer),
+		Containers:        containerInfos(kubernetesManifest),
+		Usage:             utilization,
 		OtherInfo: map[string]interface{}{
 			"kuberenetes_spec": kubernetesManifest.Spec,
 			"endpoint":         svcInfo,
@@ -143,13 +175,17 @@ func (svc DeploymentService) GetDeploymentByName(namespace, deploymentName strin
 	return deploymentInfo, nil
 }
 
-func (svc DeploymentService) GetLatestEvents(namespace string, topK int) ([]string, error) {
-	return svc.repository.Kubernetes.GetLatestEvents(namespace, topK)
-}
-
-// UpdateDeploymentReplicas updates the number of replicas for a given deployment in Kubernetes
+// UpdateDeploymentByName updates the replicas, image and resources for a given deployment in Kubernetes
 // and updates the corresponding MongoDB document.
-func (svc DeploymentService) UpdateDeploymentByName(namespace, deploymentName string, image string, replicas int32) (map[string]interface{}, error) {
+func (svc DeploymentService) UpdateDeploymentByName(namespace string, payload *model_deployment.UpdateDeploymentReq, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	deploymentName := payload.Name
+	action := model_deployment.HISTORY_UPDATE
+	if payload.ScaleOnly() {
+		action = model_deployment.HISTORY_SCALE
+	}
+	history := svc.beginHistory(namespace, deploymentName, action, actor)
+	defer func() { svc.endHistory(history, err) }()
+	replicas, image := payload.Replicas, payload.Image
 	// Retrieve the current deployment object
 	fmt.Println("143 ---- ", deploymentName, replicas, image)
 
@@ -158,7 +194,38 @@ func (svc DeploymentService) UpdateDeploymentByName(namespace, deploymentName st
 		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
 	}
 	fmt.Println("148 ---- ", deployment.Replicas, deployment.Image)
-	needToUpdateDb := false
+	if err := deployment.CheckChangeable("update"); err != nil {
+		return nil, err
+	}
+	// Canary and blue/green updates run the new image next to the deployment instead of rolling it out
+	if payload.Canary != nil {
+		return svc.startCanary(namespace, deployment, payload)
+	}
+	if payload.BlueGreen != nil {
+		return svc.startBlueGreen(namespace, deployment, payload)
+	}
+	sidecars, initContainers := deployment.Sidecars, deployment.InitContainers
+	// An update targeting a sidecar or init container leaves the main container as it is
+	if payload.Container != "" && payload.Container != deploymentName {
+		sidecars, initContainers, err = svc.updateContainer(namespace, deployment, payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = &model_deployment.UpdateDeploymentReq{
+			Name:             payload.Name,
+			Replicas:         payload.Replicas,
+			ImagePullSecrets: payload.ImagePullSecrets,
+			NetworkAccess:    payload.NetworkAccess,
+		}
+		image = ""
+	}
+	// The canary has to be promoted or aborted before the stable image changes again
+	if deployment.Canary != nil && image != "" && image != deployment.Image {
+		return nil, fmt.Errorf("deployment %s has a canary running %s, promote or abort it first", deploymentName, deployment.Canary.Image)
+	}
+	if deployment.BlueGreen != nil && image != "" && image != deployment.Image {
+		return nil, fmt.Errorf("deployment %s has a blue/green release in phase %s, wait for it or switch back first", deploymentName, deployment.BlueGreen.Phase)
+	}
 	if replicas == -1 {
 		replicas = deployment.Replicas
 
@@ -167,40 +234,181 @@ func (svc DeploymentService) UpdateDeploymentByName(namespace, deploymentName st
 		image = deployment.Image
 
 	}
-
-	// Update the replicas in Kubernetes deployment
-	if replicas != deployment.Replicas || image != deployment.Image {
-		needToUpdateDb = true
-		err := svc.repository.Kubernetes.UpdateDeploymentReplicasAndImage(namespace, deploymentName, replicas, image)
+	minReplicasMessage := ""
+	// While an autoscaler is active it owns the replica count, a manual replica count becomes its new minimum
+	if deployment.Autoscaler != nil && replicas != deployment.Replicas {
+		config := *deployment.Autoscaler
+		config.MinReplicas = replicas
+		if config.MaxReplicas < replicas {
+			return nil, fmt.Errorf("deployment %s is autoscaled up to %d replicas, raise max_replicas on the autoscaler first", deploymentName, config.MaxReplicas)
+		}
+		if _, err := svc.applyAutoscaler(namespace, deploymentName, config); err != nil {
+			return nil, err
+		}
+		minReplicasMessage = fmt.Sprintf("Deployment %s is autoscaled, updated the autoscaler min_replicas to %d", deploymentName, replicas)
+		replicas = deployment.Replicas
+	}
+	currentResources := deployment.ContainerResources("")
+	resources := currentResources
+	if payload.Resources != nil {
+		resources = payload.Resources.Merge(currentResources)
+	}
+	requirements, err := resources.ResourceRequirements()
+	if err != nil {
+		return nil, &ValidationError{Field: "resources", Err: err}
+	}
+	env := deployment.Env
+	if payload.Env != nil {
+		if err := model_deployment.ValidateEnv(payload.Env); err != nil {
+			return nil, &ValidationError{Field: "env", Err: err}
+		}
+		if err := (ConfigService{svc.repository}).ValidateEnvReferences(namespace, payload.Env); err != nil {
+			return nil, err
+		}
+		env = payload.Env
+	}
+	networkAccess := deployment.NetworkAccess
+	if payload.NetworkAccess != nil {
+		if err := model_deployment.ValidateNetworkAccess(payload.NetworkAccess); err != nil {
+			return nil, &ValidationError{Field: "network access", Err: err}
+		}
+		networkAccess = payload.NetworkAccess
+	}
+	imagePullSecrets := deployment.ImagePullSecrets
+	if payload.ImagePullSecrets != nil {
+		imagePullSecrets, err = (ConfigService{svc.repository}).ResolveImagePullSecrets(namespace, *payload.ImagePullSecrets)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update replicas in Kubernetes: %w", err)
+			return nil, err
 		}
 	}
-	if !needToUpdateDb {
+	probes := deployment.Probes
+	if payload.Probes != nil {
+		withDefaults := payload.Probes.WithDefaults(deployment.ContainerPort)
+		if err := withDefaults.Validate(); err != nil {
+			return nil, &ValidationError{Field: "probes", Err: err}
+		}
+		probes = &withDefaults
+	}
+
+	// Collect the fields that changed, they are applied to the deployment and persisted in MongoDB
+	fields := bson.M{}
+	if replicas != deployment.Replicas {
+		fields["replicas"] = replicas
+	}
+	if image != deployment.Image {
+		fields["image"] = image
+	}
+	if resources != currentResources {
+		fields["resources"] = resources
+	}
+	if !reflect.DeepEqual(env, deployment.Env) {
+		fields["env"] = env
+	}
+	if !reflect.DeepEqual(probes, deployment.Probes) {
+		fields["probes"] = probes
+	}
+	if !slices.Equal(imagePullSecrets, deployment.ImagePullSecrets) {
+		fields["image_pull_secrets"] = imagePullSecrets
+	}
+	var sidecarContainers, initK8sContainers []corev1.Container
+	if !reflect.DeepEqual(sidecars, deployment.Sidecars) {
+		fields["sidecars"] = sidecars
+		if sidecarContainers, err = model_deployment.KubernetesContainers(sidecars); err != nil {
+			return nil, err
+		}
+	}
+	if !reflect.DeepEqual(initContainers, deployment.InitContainers) {
+		fields["init_containers"] = initContainers
+		if initK8sContainers, err = model_deployment.KubernetesContainers(initContainers); err != nil {
+			return nil, err
+		}
+	}
+	// Network access lives in a network policy, it doesn't need a rollout
+	if !reflect.DeepEqual(networkAccess, deployment.NetworkAccess) {
+		if err := svc.applyNetworkAccess(namespace, deploymentName, deployment.ContainerPort, deployment.Exposure, networkAccess); err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			resp, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"network_access": networkAccess})
+			if err != nil {
+				return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+			}
+			if minReplicasMessage != "" {
+				resp["autoscaler"] = minReplicasMessage
+			}
+			return resp, nil
+		}
+		fields["network_access"] = networkAccess
+	}
+	if len(fields) == 0 && minReplicasMessage != "" {
+		return map[string]interface{}{"message": minReplicasMessage}, nil
+	}
+	if len(fields) == 0 {
 		return map[string]interface{}{
-			"message": fmt.Sprintf("Successfully updated replicas to %d and image to %s for deployment %s in Kubernetes", replicas, deploymentName, image),
+			"message": fmt.Sprintf("Successfully updated replicas to %d and image to %s for deployment %s in Kubernetes", replicas, image, deploymentName),
 		}, nil
 	}
+
+	// Update the Kubernetes deployment
+	generation, err := svc.repository.Kubernetes.UpdateDeploymentSpec(namespace, deploymentName, func(d *appsv1.Deployment) error {
+		if deployment.Autoscaler == nil {
+			d.Spec.Replicas = &replicas
+		}
+		main := adapter.MainContainer(&d.Spec.Template.Spec, deploymentName)
+		main.Image = image
+		main.Resources = requirements
+		main.Env = model_deployment.EnvVars(env)
+		if probes != nil {
+			main.LivenessProbe = probes.Liveness.KubernetesProbe()
+			main.ReadinessProbe = probes.Readiness.KubernetesProbe()
+			main.StartupProbe = probes.Startup.KubernetesProbe()
+		}
+		if _, ok := fields["sidecars"]; ok {
+			d.Spec.Template.Spec.Containers = append([]corev1.Container{*main}, sidecarContainers...)
+		}
+		if _, ok := fields["init_containers"]; ok {
+			d.Spec.Template.Spec.InitContainers = initK8sContainers
+		}
+		if _, ok := fields["image_pull_secrets"]; ok {
+			d.Spec.Template.Spec.ImagePullSecrets = adapter.LocalObjectReferences(imagePullSecrets)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update deployment in Kubernetes: %w", err)
+	}
+	// Record the ReplicaSet revision backing this update, the rollout api falls back to the live revision if it isn't known yet
+	revision, err := svc.repository.Kubernetes.WaitForDeploymentRevision(namespace, deploymentName, generation,
+		time.Duration(constants.ROLLOUT_REVISION_WAIT_SECONDS)*time.Second)
+	if err != nil {
+		logger.Logger.Warn("Error while waiting for deployment revision", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+	fields["generation"] = generation
+	fields["revision"] = revision
+
 	// Update the corresponding MongoDB document
-	resp, err := svc.updateDeploymentInMongoDB(deploymentName, image, replicas)
+	resp, err = svc.updateDeploymentInMongoDB(namespace, deploymentName, fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
 	}
+	resp["generation"] = generation
+	resp["revision"] = revision
+	if minReplicasMessage != "" {
+		resp["autoscaler"] = minReplicasMessage
+	}
 
-	fmt.Printf("Successfully updated replicas to %d and image to %s for deployment %s in Kubernetes", replicas, deploymentName, image)
+	fmt.Printf("Successfully updated replicas to %d and image to %s for deployment %s in Kubernetes", replicas, image, deploymentName)
 	return resp, nil
 }
 
-// updateDeploymentInMongoDB updates the replica count for the deployment in MongoDB's DEPLOYMENTS collection.
-func (svc DeploymentService) updateDeploymentInMongoDB(deploymentName string, image string, replicas int32) (map[string]interface{}, error) {
+// updateDeploymentInMongoDB sets the given fields on the deployment in MongoDB's DEPLOYMENTS collection.
+func (svc DeploymentService) updateDeploymentInMongoDB(namespace, deploymentName string, fields bson.M) (map[string]interface{}, error) {
 	// Construct the filter and update for MongoDB
-	fmt.Println("updating this item ", deploymentName, image, replicas)
-	filter := bson.M{"name": deploymentName}
+	fmt.Println("updating this item ", deploymentName, fields)
+	filter := bson.M{"namespace": namespace, "name": deploymentName}
+	fields["updatedAt"] = time.Now()
 	update := bson.M{
-		"$set": bson.M{
-			"image":    image,
-			"replicas": replicas,
-		},
+		"$set": fields,
 	}
 
 	// Update the MongoDB document
@@ -214,11 +422,229 @@ func (svc DeploymentService) updateDeploymentInMongoDB(deploymentName string, im
 	}, nil
 }
 
+// GetRolloutStatus reports the progress of the latest rollout of a deployment
+func (svc DeploymentService) GetRolloutStatus(namespace, deploymentName string) (*model_deployment.RolloutStatus, error) {
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	kubernetesManifest, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+
+	result, message := kubernetesManifest.RolloutResult()
+	// The reconciler records the status, the rollout may have finished since its last pass
+	status := rolloutLifecycleStatus(deployment.LifecycleStatus(), result)
+	return &model_deployment.RolloutStatus{
+		DeploymentName:     deploymentName,
+		Revision:           kubernetesManifest.Revision,
+		RecordedRevision:   deployment.Revision,
+		Generation:         kubernetesManifest.Generation,
+		ObservedGeneration: kubernetesManifest.ObservedGeneration,
+		DesiredReplicas:    kubernetesManifest.DesiredReplicas,
+		UpdatedReplicas:    kubernetesManifest.UpdatedReplicas,
+		ReadyReplicas:      kubernetesManifest.ReadyReplicas,
+		AvailableReplicas:  kubernetesManifest.AvailableReplicas,
+		ProgressingReason:  kubernetesManifest.ProgressingReason,
+		RestartedAt:        kubernetesManifest.RestartedAt,
+		Status:             status,
+		Result:             result,
+		Message:            message,
+	}, nil
+}
+
+// rolloutLifecycleStatus is the lifecycle status after a rollout result, a failed rollout marks an
+// active deployment as failed until a later rollout succeeds. Other states aren't changed by rollouts.
+func rolloutLifecycleStatus(status, result string) string {
+	switch {
+	case status == model_deployment.DEPLOYMENT_ACTIVE && (result == constants.ROLLOUT_FAILED || result == constants.ROLLOUT_TIMED_OUT):
+		return model_deployment.DEPLOYMENT_FAILED
+	case status == model_deployment.DEPLOYMENT_FAILED && result == constants.ROLLOUT_SUCCESS:
+		return model_deployment.DEPLOYMENT_ACTIVE
+	}
+	return status
+}
+
+// RestartDeployment restarts all pods of a deployment and records the restart in MongoDB
+func (svc DeploymentService) RestartDeployment(namespace, deploymentName string, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_RESTART, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if err := deployment.CheckChangeable("restart"); err != nil {
+		return nil, err
+	}
+
+	generation, restartedAt, err := svc.repository.Kubernetes.RestartDeployment(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+	// The restart rolls out a new revision, which the rollout status api follows
+	revision, err := svc.repository.Kubernetes.WaitForDeploymentRevision(namespace, deploymentName, generation,
+		time.Duration(constants.ROLLOUT_REVISION_WAIT_SECONDS)*time.Second)
+	if err != nil {
+		logger.Logger.Warn("Error while waiting for deployment revision", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+
+	resp, err = svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{
+		"restartedAt": restartedAt,
+		"generation":  generation,
+		"revision":    revision,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	resp["restartedAt"] = restartedAt
+	resp["generation"] = generation
+	resp["revision"] = revision
+	return resp, nil
+}
+
+// GetDeploymentRevisions returns the revision history of a deployment
+func (svc DeploymentService) GetDeploymentRevisions(namespace, deploymentName string) ([]adapter.DeploymentRevision, error) {
+	return svc.repository.Kubernetes.GetDeploymentRevisions(namespace, deploymentName)
+}
+
+// RollbackDeployment restores a previous revision of a deployment and keeps the DEPLOYMENTS document in sync.
+func (svc DeploymentService) RollbackDeployment(namespace, deploymentName string, revision int64, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_ROLLBACK, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if err := deployment.CheckChangeable("roll back"); err != nil {
+		return nil, err
+	}
+
+	template, generation, err := svc.repository.Kubernetes.RollbackDeployment(namespace, deploymentName, revision)
+	if err != nil {
+		return nil, err
+	}
+	main := adapter.MainContainer(&template.Spec, deploymentName)
+	image := main.Image
+	resources := model_deployment.ResourcesFromRequirements(main.Resources)
+	env := model_deployment.EnvFromEnvVars(main.Env)
+	probes := model_deployment.ProbesFromContainer(*main)
+	sidecars := model_deployment.ContainersFromKubernetes(template.Spec.Containers, deploymentName)
+	initContainers := model_deployment.ContainersFromKubernetes(template.Spec.InitContainers, deploymentName)
+	imagePullSecrets := adapter.LocalObjectNames(template.Spec.ImagePullSecrets)
+	// Rolling back creates a new revision from the restored template
+	newRevision, err := svc.repository.Kubernetes.WaitForDeploymentRevision(namespace, deploymentName, generation,
+		time.Duration(constants.ROLLOUT_REVISION_WAIT_SECONDS)*time.Second)
+	if err != nil {
+		logger.Logger.Warn("Error while waiting for deployment revision", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+
+	resp, err = svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{
+		"image":              image,
+		"resources":          resources,
+		"env":                env,
+		"probes":             probes,
+		"sidecars":           sidecars,
+		"init_containers":    initContainers,
+		"image_pull_secrets": imagePullSecrets,
+		"generation":         generation,
+		"revision":           newRevision,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	resp["image"] = image
+	resp["generation"] = generation
+	resp["revision"] = newRevision
+	return resp, nil
+}
+
+// deploymentOptions builds the pod settings of a deployment from its DEPLOYMENTS document.
+// Documents written before resources or probes were configurable get the defaults.
+func deploymentOptions(deployment *model_deployment.CreateDeploymentRequest) (adapter.DeploymentOptions, error) {
+	resources := model_deployment.DefaultDeploymentResources()
+	if deployment.Resources != nil {
+		resources = *deployment.Resources
+	}
+	requirements, err := resources.ResourceRequirements()
+	if err != nil {
+		return adapter.DeploymentOptions{}, err
+	}
+	probes := model_deployment.DefaultDeploymentProbes(deployment.ContainerPort)
+	if deployment.Probes != nil {
+		probes = *deployment.Probes
+	}
+	sidecars, err := model_deployment.KubernetesContainers(deployment.Sidecars)
+	if err != nil {
+		return adapter.DeploymentOptions{}, err
+	}
+	initContainers, err := model_deployment.KubernetesContainers(deployment.InitContainers)
+	if err != nil {
+		return adapter.DeploymentOptions{}, err
+	}
+	return adapter.DeploymentOptions{
+		Resources:        requirements,
+		Env:              model_deployment.EnvVars(deployment.Env),
+		Liveness:         probes.Liveness.KubernetesProbe(),
+		Readiness:        probes.Readiness.KubernetesProbe(),
+		Startup:          probes.Startup.KubernetesProbe(),
+		Sidecars:         sidecars,
+		InitContainers:   initContainers,
+		ImagePullSecrets: deployment.ImagePullSecrets,
+	}, nil
+}
+
+// exposeDeployment creates the Service of a deployment and the Ingress publishing it, if it is exposed through one
+func (svc DeploymentService) exposeDeployment(namespace, deploymentName string, containerPort int32, exposure model_deployment.DeploymentExposure) error {
+	if err := svc.createService(namespace, deploymentName, containerPort, exposure); err != nil {
+		return err
+	}
+	return svc.createIngress(namespace, deploymentName, exposure)
+}
+
+// recordExposure is the exposure of a deployment record, records written before exposures were stored get the defaults
+func recordExposure(record *model_deployment.CreateDeploymentRequest) model_deployment.DeploymentExposure {
+	exposure := model_deployment.DeploymentExposure{}
+	if record.Exposure != nil {
+		exposure = *record.Exposure
+	}
+	return exposure.WithDefaults()
+}
+
+func (svc DeploymentService) createService(namespace, deploymentName string, containerPort int32, exposure model_deployment.DeploymentExposure) error {
+	err := svc.repository.Kubernetes.CreateService(namespace, deploymentName+"-service",
+		deploymentName, exposure.ServicePort, containerPort, exposure.ServiceType())
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	return nil
+}
+
+// createIngress publishes the Service of a deployment exposed through an Ingress, other exposures have none
+func (svc DeploymentService) createIngress(namespace, deploymentName string, exposure model_deployment.DeploymentExposure) error {
+	if exposure.Type != model_deployment.EXPOSURE_INGRESS {
+		return nil
+	}
+	err := svc.repository.Kubernetes.CreateIngress(namespace, deploymentName+"-ingress", deploymentName+"-service",
+		constants.INGRESS_CLASS_NAME, exposure.Ingress.Host, exposure.Ingress.Path, exposure.ServicePort)
+	if err != nil {
+		return fmt.Errorf("failed to create ingress: %w", err)
+	}
+	return nil
+}
+
+// CreateNamespaceIfNotExists creates the tenant namespace and bootstraps its quota and limit range
 func (svc DeploymentService) CreateNamespaceIfNotExists(namespace string) error {
-	return svc.repository.Kubernetes.CreateNamespaceIfNotExists(namespace)
+	created, err := svc.repository.Kubernetes.CreateNamespaceIfNotExists(namespace)
+	if err != nil {
+		return err
+	}
+	return (TenantService{svc.repository}).BootstrapNamespace(namespace, created)
 }
 
-func (svc DeploymentService) CreateDeployment(payload *model_deployment.CreateDeploymentRequest) (interface{}, error) {
+func (svc DeploymentService) CreateDeployment(payload *model_deployment.CreateDeploymentRequest, actor model_deployment.Actor) (resp interface{}, err error) {
+	history := svc.beginHistory(payload.Namespace, payload.Name, model_deployment.HISTORY_CREATE, actor)
+	defer func() { svc.endHistory(history, err) }()
 	// check if build exists
 	var result bson.M
 	objectId, err := primitive.ObjectIDFromHex(payload.RepoScoutId)
@@ -230,54 +656,196 @@ func (svc DeploymentService) CreateDeployment(payload *model_deployment.CreateDe
 		fmt.Printf("FindOne error: %v\n", err1)
 		return nil, errors.New("Repo scout id not found")
 	}
-	// Create the Deployment
-	err = svc.repository.Kubernetes.CreateDeployment(payload.Namespace, payload.Name,
-		payload.Image, payload.Replicas, payload.ContainerPort, "50m", "0.2Gi")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create deployment: %w", err)
+	// Resolve the container resources, anything not requested falls back to the defaults
+	resources := model_deployment.DefaultDeploymentResources()
+	if payload.Resources != nil {
+		resources = payload.Resources.Merge(resources)
 	}
-
-	err = svc.repository.Kubernetes.CreateService(payload.Namespace, payload.Name+"-service",
-		payload.Name, 80, payload.ContainerPort)
+	payload.Resources = &resources
+	if err := model_deployment.ValidateEnv(payload.Env); err != nil {
+		return nil, err
+	}
+	if err := (ConfigService{svc.repository}).ValidateEnvReferences(payload.Namespace, payload.Env); err != nil {
+		return nil, err
+	}
+	if err := model_deployment.ValidateContainers(payload.Name, payload.Sidecars, payload.InitContainers); err != nil {
+		return nil, err
+	}
+	for _, container := range append(append([]model_deployment.Container{}, payload.Sidecars...), payload.InitContainers...) {
+		if err := (ConfigService{svc.repository}).ValidateEnvReferences(payload.Namespace, container.Env); err != nil {
+			return nil, fmt.Errorf("container %s: %w", container.Name, err)
+		}
+	}
+	// Deployments without probes get tcp checks on the container port
+	probes := model_deployment.DefaultDeploymentProbes(payload.ContainerPort)
+	if payload.Probes != nil {
+		probes = payload.Probes.WithDefaults(payload.ContainerPort)
+		if err := probes.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	payload.Probes = &probes
+	// Deployments without an exposure keep the public load balancer on port 80
+	exposure := model_deployment.DeploymentExposure{}
+	if payload.Exposure != nil {
+		exposure = *payload.Exposure
+	}
+	exposure = exposure.WithDefaults()
+	if err := exposure.Validate(); err != nil {
+		return nil, err
+	}
+	payload.Exposure = &exposure
+	if err := model_deployment.ValidateNetworkAccess(payload.NetworkAccess); err != nil {
+		return nil, err
+	}
+	// Without a selection the deployment pulls with every registry credential of the tenant
+	imagePullSecrets, err := (ConfigService{svc.repository}).ResolveImagePullSecrets(payload.Namespace, payload.ImagePullSecrets)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create service: %w", err)
+		return nil, err
 	}
-	payload.Status = "ACTIVE"
-
-	// Insert the deployment data into MongoDB
-	_, err = svc.repository.MongoDB.InsertOne("DEPLOYMENTS", payload)
+	payload.ImagePullSecrets = imagePullSecrets
+	options, err := deploymentOptions(payload)
 	if err != nil {
-		logger.Logger.Error("Error while inserting new deployment", zap.Any(logger.KEY_ERROR, err.Error()))
 		return nil, err
 	}
-	fmt.Println("repo scout id is ", payload.RepoScoutId)
-	// update repo scout based on RepoScoutId from payload
-	repo_scout_id, err := primitive.ObjectIDFromHex(payload.RepoScoutId)
-	fmt.Println("repo scout is is , ", repo_scout_id, err)
-	filter := bson.M{"_id": repo_scout_id}
 
-	// Define the update operation to push the new DeploymentID to the Deployments array
-	update := bson.M{
-		"$push": bson.M{"deployments": payload.Name},
-		"$set":  bson.M{"updatedAt": time.Now()},
-	}
+	payload.Status = model_deployment.DEPLOYMENT_ACTIVE
+	payload.Paused = nil
 
-	// Perform the update operation
-	scout_repo_result, err := svc.repository.MongoDB.UpdateOne("REPO_SCOUTS", filter, update)
-	if err != nil {
-		logger.Logger.Error("Error while updating repo scouts", zap.Any(logger.KEY_ERROR, err.Error()))
+	// A record means the deployment exists, without one an abandoned attempt may have left resources behind
+	if _, err := svc.GetDeploymentFromDBByName(payload.Namespace, payload.Name); err == nil {
+		return nil, fmt.Errorf("deployment %s already exists in namespace %s", payload.Name, payload.Namespace)
+	}
+	steps := svc.createSteps(payload, options)
+	if err := svc.recoverCreate(payload.Namespace, payload.Name, steps); err != nil {
 		return nil, err
 	}
-
-	// Log success if document was updated
-	if scout_repo_result.ModifiedCount > 0 {
-		logger.Logger.Info("RepoScout updated successfully", zap.Any("RepoScoutId", payload.ID))
-	} else {
-		logger.Logger.Warn("No RepoScout document found with specified RepoScoutId", zap.Any("RepoScoutId", payload.ID))
+	if _, err := svc.runOperation(model_deployment.OPERATION_CREATE, payload.Namespace, payload.Name, steps); err != nil {
+		logger.Logger.Error("Error while creating deployment", zap.Any(logger.KEY_ERROR, err.Error()))
+		return nil, err
 	}
 	return payload, nil
 }
 
+// createSteps creates the Deployment, its Service and network policy, then records it in MongoDB and on its repo scout
+func (svc DeploymentService) createSteps(payload *model_deployment.CreateDeploymentRequest, options adapter.DeploymentOptions) []operationStep {
+	namespace, deploymentName := payload.Namespace, payload.Name
+	// CreateDeployment has checked the repo scout id
+	repoScoutId, _ := primitive.ObjectIDFromHex(payload.RepoScoutId)
+	repoScoutFilter := bson.M{"_id": repoScoutId}
+	return []operationStep{
+		{
+			name: "deployment",
+			run: func() error {
+				err := svc.repository.Kubernetes.CreateDeployment(namespace, deploymentName,
+					payload.Image, payload.Replicas, payload.ContainerPort, options)
+				if err != nil {
+					return fmt.Errorf("failed to create deployment: %w", err)
+				}
+				return nil
+			},
+			compensate: func() error {
+				return svc.repository.Kubernetes.DeleteDeployment(namespace, deploymentName)
+			},
+		},
+		{
+			name: "service",
+			run: func() error {
+				return svc.createService(namespace, deploymentName, payload.ContainerPort, *payload.Exposure)
+			},
+			compensate: func() error {
+				return svc.repository.Kubernetes.DeleteService(namespace, deploymentName+"-service")
+			},
+		},
+		{
+			name: "ingress",
+			run: func() error {
+				return svc.createIngress(namespace, deploymentName, *payload.Exposure)
+			},
+			compensate: func() error {
+				return svc.repository.Kubernetes.DeleteIngress(namespace, deploymentName+"-ingress")
+			},
+		},
+		{
+			name: "network-policy",
+			run: func() error {
+				err := svc.applyNetworkAccess(namespace, deploymentName, payload.ContainerPort, payload.Exposure, payload.NetworkAccess)
+				if err != nil {
+					return fmt.Errorf("failed to apply network policy: %w", err)
+				}
+				return nil
+			},
+			compensate: func() error {
+				return svc.repository.Kubernetes.DeleteNetworkPolicy(namespace, networkPolicyName(deploymentName))
+			},
+		},
+		{
+			name: "record",
+			run: func() error {
+				_, err := svc.repository.MongoDB.InsertOne("DEPLOYMENTS", payload)
+				return err
+			},
+			compensate: func() error {
+				_, err := svc.repository.MongoDB.DeleteOne("DEPLOYMENTS", bson.M{"namespace": namespace, "name": deploymentName})
+				return err
+			},
+		},
+		{
+			name: "repo-scout",
+			run: func() error {
+				// Push the new deployment to the deployments of its repo scout
+				scoutRepoResult, err := svc.repository.MongoDB.UpdateOne("REPO_SCOUTS", repoScoutFilter, bson.M{
+					"$addToSet": bson.M{"deployments": deploymentName},
+					"$set":      bson.M{"updatedAt": time.Now()},
+				})
+				if err != nil {
+					return err
+				}
+				if scoutRepoResult.MatchedCount > 0 {
+					logger.Logger.Info("RepoScout updated successfully", zap.String("RepoScoutId", payload.RepoScoutId))
+				} else {
+					logger.Logger.Warn("No RepoScout document found with specified RepoScoutId", zap.String("RepoScoutId", payload.RepoScoutId))
+				}
+				return nil
+			},
+			compensate: func() error {
+				_, err := svc.repository.MongoDB.UpdateOne("REPO_SCOUTS", repoScoutFilter, bson.M{
+					"$pull": bson.M{"deployments": deploymentName},
+					"$set":  bson.M{"updatedAt": time.Now()},
+				})
+				return err
+			},
+		},
+	}
+}
+
+// recoverCreate cleans up after the latest create of a deployment if it was abandoned half way or its rollback failed,
+// so that a retry doesn't run into the resources it left behind. A create that is still running is refused.
+func (svc DeploymentService) recoverCreate(namespace, deploymentName string, steps []operationStep) error {
+	op, err := svc.latestOperation(model_deployment.OPERATION_CREATE, namespace, deploymentName)
+	if err != nil || op == nil {
+		return err
+	}
+	switch op.Status {
+	case model_deployment.OPERATION_RUNNING:
+		if time.Since(op.StartedAt) < time.Duration(constants.OPERATION_STALE_SECONDS)*time.Second {
+			return fmt.Errorf("deployment %s is already being created", deploymentName)
+		}
+	case model_deployment.OPERATION_FAILED:
+	default:
+		return nil
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := steps[i].compensate(); err != nil {
+			svc.recordStep(op, steps[i].name, model_deployment.STEP_COMPENSATION_FAILED, err)
+			return fmt.Errorf("failed to clean up the previous create of deployment %s: %w", deploymentName, err)
+		}
+		svc.recordStep(op, steps[i].name, model_deployment.STEP_COMPENSATED, nil)
+	}
+	svc.finishOperation(op, model_deployment.OPERATION_ROLLED_BACK, nil)
+	return nil
+}
+
 func (svc DeploymentService) GetAllDeploymentsFromDBByNamespace(namespace string) ([]model_deployment.CreateDeploymentRequest, error) {
 
 	filter := bson.M{"namespace": namespace}
@@ -314,52 +882,184 @@ func (svc DeploymentService) GetDeploymentFromDBByName(namespace, deplyomentName
 	return &result, nil
 }
 
-func (svc DeploymentService) DeleteDeployment(namespace, deploymentName, RepoScoutId string) (map[string]interface{}, error) {
-	// Delete the Deployment from Kubernetes
-	err := svc.repository.Kubernetes.DeleteDeployment(namespace, deploymentName)
-	if err != nil {
-		// return nil, fmt.Errorf("failed to delete deployment: %w", err)
-	}
-
-	// Delete the associated Service from Kubernetes
-	err = svc.repository.Kubernetes.DeleteService(namespace, deploymentName+"-service")
-	if err != nil {
-		return nil, fmt.Errorf("failed to delete service: %w", err)
+func (svc DeploymentService) DeleteDeployment(namespace, deploymentName, RepoScoutId string, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_DELETE, actor)
+	defer func() { svc.endHistory(history, err) }()
+	// Without a record there is nothing to restore, the remaining resources are still removed
+	record, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
 	}
-
-	// Remove the deployment record from MongoDB
-	filter := bson.M{"namespace": namespace, "name": deploymentName}
-	deploymentDeleteResult, err := svc.repository.MongoDB.DeleteOne("DEPLOYMENTS", filter)
+	op, err := svc.runOperation(model_deployment.OPERATION_DELETE, namespace, deploymentName, svc.deleteSteps(namespace, deploymentName, RepoScoutId, record))
 	if err != nil {
-		logger.Logger.Error("Error while deleting deployment from MongoDB", zap.Any(logger.KEY_ERROR, err.Error()))
+		logger.Logger.Error("Error while deleting deployment", zap.Any(logger.KEY_ERROR, err.Error()))
 		return nil, err
 	}
+	return map[string]interface{}{
+		"operation": op,
+	}, nil
+}
 
-	// Update the RepoScout document to remove the deployment reference
-	repo_scout_id, err := primitive.ObjectIDFromHex(RepoScoutId)
-	fmt.Println("repo scout is is , ", repo_scout_id, err)
-	updateFilter := bson.M{"_id": repo_scout_id}
-	update := bson.M{
-		"$pull": bson.M{"deployments": deploymentName},
-		"$set":  bson.M{"updatedAt": time.Now()},
+// deleteSteps removes a deployment in the reverse order of its creation. Every step is undone from the record
+// if a later one fails, so a failed delete leaves the deployment running as it was.
+func (svc DeploymentService) deleteSteps(namespace, deploymentName, repoScoutId string, record *model_deployment.CreateDeploymentRequest) []operationStep {
+	filter := bson.M{"namespace": namespace, "name": deploymentName}
+	fromRecord := func(restore func() error) func() error {
+		if record == nil {
+			return nil
+		}
+		return restore
 	}
-
-	repoScoutUpdateResult, err := svc.repository.MongoDB.UpdateOne("REPO_SCOUTS", updateFilter, update)
-	if err != nil {
-		logger.Logger.Error("Error while updating RepoScout in MongoDB", zap.Any(logger.KEY_ERROR, err.Error()))
-		return nil, err
+	// Scaling schedules of a deleted deployment would only fail from now on, they are restored on a rollback
+	var schedules []interface{}
+	if found, err := svc.GetSchedules(namespace, deploymentName); err == nil {
+		for _, schedule := range found {
+			schedules = append(schedules, schedule)
+		}
 	}
+	repoScoutObjectId, _ := primitive.ObjectIDFromHex(repoScoutId)
+	repoScoutFilter := bson.M{"_id": repoScoutObjectId}
 
-	// Log success if the RepoScout document was updated
-	if repoScoutUpdateResult.ModifiedCount > 0 {
-		logger.Logger.Info("RepoScout updated successfully", zap.String("RepoScoutId", RepoScoutId))
-	} else {
-		logger.Logger.Warn("No RepoScout document found with specified RepoScoutId", zap.String("RepoScoutId", RepoScoutId))
+	return []operationStep{
+		{
+			// Block further changes while the resources are removed
+			name: "status",
+			run: func() error {
+				_, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"status": model_deployment.DEPLOYMENT_DELETING})
+				return err
+			},
+			compensate: fromRecord(func() error {
+				_, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"status": record.LifecycleStatus()})
+				return err
+			}),
+		},
+		{
+			name: "network-policy",
+			run: func() error {
+				return svc.repository.Kubernetes.DeleteNetworkPolicy(namespace, networkPolicyName(deploymentName))
+			},
+			compensate: fromRecord(func() error {
+				return svc.applyNetworkAccess(namespace, deploymentName, record.ContainerPort, record.Exposure, record.NetworkAccess)
+			}),
+		},
+		{
+			// The Ingress publishing the Service goes first, if the deployment was exposed through one
+			name: "ingress",
+			run: func() error {
+				if err := svc.repository.Kubernetes.DeleteIngress(namespace, deploymentName+"-ingress"); err != nil {
+					return fmt.Errorf("failed to delete ingress: %w", err)
+				}
+				return nil
+			},
+			compensate: fromRecord(func() error {
+				return svc.createIngress(namespace, deploymentName, recordExposure(record))
+			}),
+		},
+		{
+			name: "service",
+			run: func() error {
+				if err := svc.repository.Kubernetes.DeleteService(namespace, deploymentName+"-service"); err != nil {
+					return fmt.Errorf("failed to delete service: %w", err)
+				}
+				return nil
+			},
+			compensate: fromRecord(func() error {
+				return svc.createService(namespace, deploymentName, record.ContainerPort, recordExposure(record))
+			}),
+		},
+		{
+			// The autoscaler goes before its target so it doesn't outlive it
+			name: "autoscaler",
+			run: func() error {
+				return svc.repository.Kubernetes.DeleteHorizontalPodAutoscaler(namespace, autoscalerName(deploymentName))
+			},
+			compensate: fromRecord(func() error {
+				if record.Autoscaler == nil {
+					return nil
+				}
+				config := record.Autoscaler
+				return svc.repository.Kubernetes.ApplyHorizontalPodAutoscaler(namespace, autoscalerName(deploymentName), deploymentName,
+					config.MinReplicas, config.MaxReplicas, config.TargetCPUUtilization, config.TargetMemoryUtilization)
+			}),
+		},
+		{
+			name: "deployment",
+			run: func() error {
+				if err := svc.repository.Kubernetes.DeleteDeployment(namespace, deploymentName); err != nil {
+					return fmt.Errorf("failed to delete deployment: %w", err)
+				}
+				return nil
+			},
+			compensate: fromRecord(func() error {
+				options, err := deploymentOptions(record)
+				if err != nil {
+					return err
+				}
+				return svc.repository.Kubernetes.CreateDeployment(namespace, deploymentName, record.Image, record.Replicas, record.ContainerPort, options)
+			}),
+		},
+		{
+			// The canary and the parallel deployments of a blue/green release, if any. They only run next
+			// to the deployment, a rollback doesn't bring them back and the record drops their state.
+			name: "companions",
+			run: func() error {
+				if err := svc.repository.Kubernetes.DeleteDeployment(namespace, canaryName(deploymentName)); err != nil {
+					return fmt.Errorf("failed to delete canary: %w", err)
+				}
+				for _, color := range []string{colorBlue, colorGreen} {
+					if err := svc.repository.Kubernetes.DeleteDeployment(namespace, blueGreenName(deploymentName, color)); err != nil {
+						return fmt.Errorf("failed to delete %s deployment: %w", color, err)
+					}
+				}
+				return nil
+			},
+			compensate: fromRecord(func() error {
+				_, err := svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", filter, bson.M{"$unset": bson.M{"canary": "", "blue_green": ""}})
+				return err
+			}),
+		},
+		{
+			name: "schedules",
+			run: func() error {
+				return svc.deleteSchedules(namespace, deploymentName)
+			},
+			compensate: func() error {
+				if len(schedules) == 0 {
+					return nil
+				}
+				_, err := svc.repository.MongoDB.InsertMany("SCHEDULES", schedules)
+				return err
+			},
+		},
+		{
+			name: "record",
+			run: func() error {
+				_, err := svc.repository.MongoDB.DeleteOne("DEPLOYMENTS", filter)
+				return err
+			},
+			compensate: fromRecord(func() error {
+				_, err := svc.repository.MongoDB.InsertOne("DEPLOYMENTS", record)
+				return err
+			}),
+		},
+		{
+			// Remove the deployment reference from its repo scout
+			name: "repo-scout",
+			run: func() error {
+				repoScoutUpdateResult, err := svc.repository.MongoDB.UpdateOne("REPO_SCOUTS", repoScoutFilter, bson.M{
+					"$pull": bson.M{"deployments": deploymentName},
+					"$set":  bson.M{"updatedAt": time.Now()},
+				})
+				if err != nil {
+					return err
+				}
+				if repoScoutUpdateResult.ModifiedCount > 0 {
+					logger.Logger.Info("RepoScout updated successfully", zap.String("RepoScoutId", repoScoutId))
+				} else {
+					logger.Logger.Warn("No RepoScout document found with specified RepoScoutId", zap.String("RepoScoutId", repoScoutId))
+				}
+				return nil
+			},
+		},
 	}
-
-	// Return details of the delete operation
-	return map[string]interface{}{
-		"deployment_delete_result": deploymentDeleteResult,
-		"repo_scout_update_result": repoScoutUpdateResult,
-	}, nil
 }
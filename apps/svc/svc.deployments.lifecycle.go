@@ -0,0 +1,108 @@
+package svc
+
+import (
+	model_deployment "deployment-service/models/model.deployment"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func (svc DeploymentService) scaleDeployment(namespace, deploymentName string, replicas int32) error {
+	_, err := svc.repository.Kubernetes.UpdateDeploymentSpec(namespace, deploymentName, func(d *appsv1.Deployment) error {
+		d.Spec.Replicas = &replicas
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale deployment %s to %d replicas: %w", deploymentName, replicas, err)
+	}
+	return nil
+}
+
+// PauseDeployment scales a deployment to 0 and remembers its replica count and status for the resume
+func (svc DeploymentService) PauseDeployment(namespace, deploymentName string, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_SCALE, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if err := deployment.CheckChangeable("pause"); err != nil {
+		return nil, err
+	}
+	if deployment.Canary != nil {
+		return nil, fmt.Errorf("deployment %s has a canary running %s, promote or abort it first", deploymentName, deployment.Canary.Image)
+	}
+	if deployment.BlueGreen != nil {
+		return nil, fmt.Errorf("deployment %s has a blue/green release in phase %s, wait for it or switch back first", deploymentName, deployment.BlueGreen.Phase)
+	}
+
+	// An autoscaler owns the replica count, the live count is the one to come back to.
+	// The autoscaler itself stays, it doesn't scale a deployment that has 0 replicas.
+	replicas := deployment.Replicas
+	if deployment.Autoscaler != nil {
+		kubernetesManifest, err := svc.repository.Kubernetes.GetDeploymentByName(namespace, deploymentName)
+		if err != nil {
+			return nil, err
+		}
+		replicas = kubernetesManifest.DesiredReplicas
+	}
+	if err := svc.scaleDeployment(namespace, deploymentName, 0); err != nil {
+		return nil, err
+	}
+
+	paused := model_deployment.PausedState{
+		Replicas: replicas,
+		Status:   deployment.LifecycleStatus(),
+		PausedAt: time.Now(),
+	}
+	resp, err = svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{
+		"status":   model_deployment.DEPLOYMENT_PAUSED,
+		"replicas": int32(0),
+		"paused":   paused,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	resp["paused"] = paused
+	return resp, nil
+}
+
+// ResumeDeployment scales a paused deployment back to its previous replica count and restores its status
+func (svc DeploymentService) ResumeDeployment(namespace, deploymentName string, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_SCALE, actor)
+	defer func() { svc.endHistory(history, err) }()
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if deployment.LifecycleStatus() != model_deployment.DEPLOYMENT_PAUSED || deployment.Paused == nil {
+		return nil, fmt.Errorf("deployment %s is not paused", deploymentName)
+	}
+	paused := deployment.Paused
+	if err := svc.scaleDeployment(namespace, deploymentName, paused.Replicas); err != nil {
+		return nil, err
+	}
+
+	status := paused.Status
+	if status == "" || status == model_deployment.DEPLOYMENT_PAUSED {
+		status = model_deployment.DEPLOYMENT_ACTIVE
+	}
+	res, err := svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", bson.M{"namespace": namespace, "name": deploymentName}, bson.M{
+		"$set": bson.M{
+			"status":    status,
+			"replicas":  paused.Replicas,
+			"updatedAt": time.Now(),
+		},
+		"$unset": bson.M{"paused": ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+	}
+	return map[string]interface{}{
+		"result":   res,
+		"status":   status,
+		"replicas": paused.Replicas,
+	}, nil
+}
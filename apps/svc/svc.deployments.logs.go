@@ -0,0 +1,103 @@
+package svc
+
+import (
+	"bufio"
+	"context"
+	adapter "deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StreamDeploymentLogs merges the logs of the deployment's pods into a single channel.
+// The channel is closed once every stream has ended or ctx is cancelled.
+func (svc DeploymentService) StreamDeploymentLogs(ctx context.Context, namespace, deploymentName string, query model_deployment.LogsQuery) (<-chan model_deployment.LogLine, error) {
+	pods, err := svc.repository.Kubernetes.GetDeploymentPods(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+	if query.Pod != "" {
+		var selected []corev1.Pod
+		for _, pod := range pods {
+			if pod.Name == query.Pod {
+				selected = append(selected, pod)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("pod %s does not belong to deployment %s", query.Pod, deploymentName)
+		}
+		pods = selected
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods found for deployment %s", deploymentName)
+	}
+
+	tailLines := query.TailLines
+	if tailLines == nil && !query.Follow && query.SinceSeconds == nil {
+		defaultTail := int64(constants.LOGS_DEFAULT_TAIL_LINES)
+		tailLines = &defaultTail
+	}
+
+	lines := make(chan model_deployment.LogLine)
+	var wg sync.WaitGroup
+	var lastErr error
+	opened := 0
+	for _, pod := range pods {
+		container := query.Container
+		if container == "" {
+			container = adapter.MainContainer(&pod.Spec, deploymentName).Name
+		}
+		stream, err := svc.repository.Kubernetes.GetPodLogStream(ctx, namespace, pod.Name, &corev1.PodLogOptions{
+			Container:    container,
+			Follow:       query.Follow,
+			Previous:     query.Previous,
+			TailLines:    tailLines,
+			SinceSeconds: query.SinceSeconds,
+		})
+		if err != nil {
+			// A pod that is still starting, or has no previous container, shouldn't hide the logs of the others
+			logger.Logger.Warn("Error while opening pod log stream", zap.String("pod", pod.Name), zap.Any(logger.KEY_ERROR, err.Error()))
+			lastErr = err
+			continue
+		}
+		opened++
+
+		wg.Add(1)
+		go func(podName, container string) {
+			defer wg.Done()
+			defer stream.Close()
+			scanner := bufio.NewScanner(stream)
+			scanner.Buffer(make([]byte, 0, 64*1024), constants.LOGS_MAX_LINE_BYTES)
+			for scanner.Scan() {
+				select {
+				case lines <- model_deployment.LogLine{Pod: podName, Container: container, Line: scanner.Text()}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			// A line longer than the buffer, or a broken stream, ends this pod's logs; tell the client why
+			if err := scanner.Err(); err != nil && ctx.Err() == nil {
+				logger.Logger.Warn("Error while reading pod log stream", zap.String("pod", podName), zap.Any(logger.KEY_ERROR, err.Error()))
+				select {
+				case lines <- model_deployment.LogLine{Pod: podName, Container: container, Error: err.Error()}:
+				case <-ctx.Done():
+				}
+			}
+		}(pod.Name, container)
+	}
+
+	if opened == 0 {
+		return nil, lastErr
+	}
+
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+	return lines, nil
+}
@@ -0,0 +1,37 @@
+package svc
+
+import (
+	adapter "deployment-service/apps/repository/adapter"
+	model_deployment "deployment-service/models/model.deployment"
+)
+
+func networkPolicyName(deploymentName string) string {
+	return deploymentName + "-network"
+}
+
+// applyNetworkAccess opens the deployment's pods beyond the namespace isolation policies.
+// Load balancer and node port deployments keep their container port public, network access
+// rules open ports to other tenants' services. Deployments needing neither have no policy.
+func (svc DeploymentService) applyNetworkAccess(namespace, deploymentName string, containerPort int32,
+	exposure *model_deployment.DeploymentExposure, rules []model_deployment.NetworkAccessRule) error {
+	var publicPorts []int32
+	if exposure != nil && (exposure.Type == model_deployment.EXPOSURE_LOAD_BALANCER || exposure.Type == model_deployment.EXPOSURE_NODE_PORT) {
+		publicPorts = append(publicPorts, containerPort)
+	}
+	var peers []adapter.NetworkPeer
+	for _, rule := range rules {
+		ports := rule.Ports
+		if len(ports) == 0 {
+			ports = []int32{containerPort}
+		}
+		peers = append(peers, adapter.NetworkPeer{
+			Namespace: rule.Tenant,
+			App:       rule.Service,
+			Ports:     ports,
+		})
+	}
+	if len(publicPorts) == 0 && len(peers) == 0 {
+		return svc.repository.Kubernetes.DeleteNetworkPolicy(namespace, networkPolicyName(deploymentName))
+	}
+	return svc.repository.Kubernetes.ApplyDeploymentNetworkPolicy(namespace, networkPolicyName(deploymentName), deploymentName, publicPorts, peers)
+}
@@ -0,0 +1,103 @@
+package svc
+
+import (
+	adapter "deployment-service/apps/repository/adapter"
+	model_deployment "deployment-service/models/model.deployment"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetDeploymentPods lists the pods of a deployment with their container states
+func (svc DeploymentService) GetDeploymentPods(namespace, deploymentName string) ([]model_deployment.PodInfo, error) {
+	if _, err := svc.GetDeploymentFromDBByName(namespace, deploymentName); err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	pods, err := svc.repository.Kubernetes.GetDeploymentPods(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+
+	var podInfos = []model_deployment.PodInfo{}
+	for _, pod := range pods {
+		podInfo := model_deployment.PodInfo{
+			Name:            pod.Name,
+			Phase:           string(pod.Status.Phase),
+			TotalContainers: len(pod.Spec.Containers),
+			Node:            pod.Spec.NodeName,
+			PodIP:           pod.Status.PodIP,
+			Age:             adapter.FormatAge(pod.CreationTimestamp.Time),
+			Containers:      []model_deployment.PodContainerStatus{},
+		}
+		// A pod being deleted still reports Running, show it as kubectl does
+		if pod.DeletionTimestamp != nil {
+			podInfo.Phase = "Terminating"
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			podInfo.Containers = append(podInfo.Containers, podContainerStatus(status, true))
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			containerStatus := podContainerStatus(status, false)
+			if status.Ready {
+				podInfo.ReadyContainers++
+			}
+			podInfo.Restarts += status.RestartCount
+			if containerStatus.LastTerminationReason != "" {
+				podInfo.LastTerminationReason = containerStatus.LastTerminationReason
+			}
+			podInfo.Containers = append(podInfo.Containers, containerStatus)
+		}
+		podInfos = append(podInfos, podInfo)
+	}
+	sort.Slice(podInfos, func(i, j int) bool { return podInfos[i].Name < podInfos[j].Name })
+	return podInfos, nil
+}
+
+// podContainerStatus maps the kubernetes container status, the reason explains waiting and terminated states
+func podContainerStatus(status corev1.ContainerStatus, init bool) model_deployment.PodContainerStatus {
+	containerStatus := model_deployment.PodContainerStatus{
+		Name:         status.Name,
+		Init:         init,
+		Ready:        status.Ready,
+		RestartCount: status.RestartCount,
+	}
+	switch {
+	case status.State.Running != nil:
+		containerStatus.State = "Running"
+	case status.State.Waiting != nil:
+		containerStatus.State = "Waiting"
+		containerStatus.Reason = status.State.Waiting.Reason
+	case status.State.Terminated != nil:
+		containerStatus.State = "Terminated"
+		containerStatus.Reason = status.State.Terminated.Reason
+	}
+	if status.LastTerminationState.Terminated != nil {
+		containerStatus.LastTerminationReason = status.LastTerminationState.Terminated.Reason
+	}
+	return containerStatus
+}
+
+// DeleteDeploymentPod deletes one pod of a deployment so its controller recreates it
+func (svc DeploymentService) DeleteDeploymentPod(namespace, deploymentName, podName string) (map[string]interface{}, error) {
+	if _, err := svc.GetDeploymentFromDBByName(namespace, deploymentName); err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	pods, err := svc.repository.Kubernetes.GetDeploymentPods(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+	// Only pods selected by the deployment can be deleted through it
+	for _, pod := range pods {
+		if pod.Name != podName {
+			continue
+		}
+		if err := svc.repository.Kubernetes.DeletePod(namespace, podName); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"message": fmt.Sprintf("Deleted pod %s of deployment %s, it will be recreated", podName, deploymentName),
+		}, nil
+	}
+	return nil, fmt.Errorf("pod %s not found in deployment %s", podName, deploymentName)
+}
@@ -0,0 +1,139 @@
+package svc
+
+import (
+	"deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// ApplyDeploymentSpec makes a deployment run the given full spec with server-side apply and keeps the DEPLOYMENTS
+// document in sync. It returns the fields the apply changed, applying the spec a deployment already runs changes nothing.
+func (svc DeploymentService) ApplyDeploymentSpec(namespace, deploymentName string, payload *model_deployment.DeploymentSpecReq, actor model_deployment.Actor) (resp map[string]interface{}, err error) {
+	history := svc.beginHistory(namespace, deploymentName, model_deployment.HISTORY_UPDATE, actor)
+	defer func() { svc.endHistory(history, err) }()
+
+	deployment, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	if err := deployment.CheckChangeable("apply a spec to"); err != nil {
+		return nil, err
+	}
+	if deployment.Canary != nil {
+		return nil, fmt.Errorf("deployment %s has a canary running %s, promote or abort it first", deploymentName, deployment.Canary.Image)
+	}
+	if deployment.BlueGreen != nil {
+		return nil, fmt.Errorf("deployment %s has a blue/green release in phase %s, wait for it or switch back first", deploymentName, deployment.BlueGreen.Phase)
+	}
+	var replicas *int32
+	if deployment.Autoscaler != nil {
+		if payload.Replicas != nil {
+			return nil, fmt.Errorf("deployment %s is autoscaled, leave replicas out of its spec or change the autoscaler", deploymentName)
+		}
+	} else {
+		if payload.Replicas == nil {
+			return nil, fmt.Errorf("replicas is required in the spec of deployment %s", deploymentName)
+		}
+		replicas = payload.Replicas
+	}
+
+	// Resolve the desired record the same way a create does
+	desired := *deployment
+	desired.Image = payload.Image
+	if replicas != nil {
+		desired.Replicas = *replicas
+	}
+	resources := model_deployment.DefaultDeploymentResources()
+	if payload.Resources != nil {
+		resources = payload.Resources.Merge(resources)
+	}
+	desired.Resources = &resources
+	if err := model_deployment.ValidateEnv(payload.Env); err != nil {
+		return nil, err
+	}
+	if err := (ConfigService{svc.repository}).ValidateEnvReferences(namespace, payload.Env); err != nil {
+		return nil, err
+	}
+	desired.Env = payload.Env
+	if err := model_deployment.ValidateContainers(deploymentName, payload.Sidecars, payload.InitContainers); err != nil {
+		return nil, err
+	}
+	for _, container := range append(append([]model_deployment.Container{}, payload.Sidecars...), payload.InitContainers...) {
+		if err := (ConfigService{svc.repository}).ValidateEnvReferences(namespace, container.Env); err != nil {
+			return nil, fmt.Errorf("container %s: %w", container.Name, err)
+		}
+	}
+	desired.Sidecars, desired.InitContainers = payload.Sidecars, payload.InitContainers
+	probes := model_deployment.DefaultDeploymentProbes(deployment.ContainerPort)
+	if payload.Probes != nil {
+		probes = payload.Probes.WithDefaults(deployment.ContainerPort)
+		if err := probes.Validate(); err != nil {
+			return nil, &ValidationError{Field: "probes", Err: err}
+		}
+	}
+	desired.Probes = &probes
+	if desired.ImagePullSecrets, err = (ConfigService{svc.repository}).ResolveImagePullSecrets(namespace, payload.ImagePullSecrets); err != nil {
+		return nil, err
+	}
+	options, err := deploymentOptions(&desired)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after, err := svc.repository.Kubernetes.ApplyDeploymentSpec(namespace, deploymentName, replicas,
+		adapter.SpecPodSpec(deploymentName, desired.Image, options))
+	if err != nil {
+		return nil, err
+	}
+	changes := adapter.DiffDeploymentSpecs(before, after)
+
+	// Persist the fields of the record the spec changed
+	fields := bson.M{}
+	if desired.Image != deployment.Image {
+		fields["image"] = desired.Image
+	}
+	if desired.Replicas != deployment.Replicas {
+		fields["replicas"] = desired.Replicas
+	}
+	for field, values := range map[string][2]interface{}{
+		"resources":       {desired.Resources, deployment.Resources},
+		"env":             {desired.Env, deployment.Env},
+		"probes":          {desired.Probes, deployment.Probes},
+		"sidecars":        {desired.Sidecars, deployment.Sidecars},
+		"init_containers": {desired.InitContainers, deployment.InitContainers},
+	} {
+		if !reflect.DeepEqual(values[0], values[1]) {
+			fields[field] = values[0]
+		}
+	}
+	if !slices.Equal(desired.ImagePullSecrets, deployment.ImagePullSecrets) {
+		fields["image_pull_secrets"] = desired.ImagePullSecrets
+	}
+	// A changed pod template rolls out a new revision, which the rollout status api follows
+	if after.Generation != before.Generation {
+		revision, err := svc.repository.Kubernetes.WaitForDeploymentRevision(namespace, deploymentName, after.Generation,
+			time.Duration(constants.ROLLOUT_REVISION_WAIT_SECONDS)*time.Second)
+		if err != nil {
+			logger.Logger.Warn("Error while waiting for deployment revision", zap.Any(logger.KEY_ERROR, err.Error()))
+		}
+		fields["generation"] = after.Generation
+		fields["revision"] = revision
+	}
+	if len(fields) > 0 {
+		if _, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, fields); err != nil {
+			return nil, fmt.Errorf("failed to update MongoDB for deployment %s: %w", deploymentName, err)
+		}
+	}
+	return map[string]interface{}{
+		"changes":    changes,
+		"generation": after.Generation,
+	}, nil
+}
@@ -0,0 +1,27 @@
+package svc
+
+import (
+	"testing"
+
+	"deployment-service/constants"
+	model_deployment "deployment-service/models/model.deployment"
+)
+
+func TestRolloutLifecycleStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status, result, want string
+	}{
+		{model_deployment.DEPLOYMENT_ACTIVE, constants.ROLLOUT_FAILED, model_deployment.DEPLOYMENT_FAILED},
+		{model_deployment.DEPLOYMENT_ACTIVE, constants.ROLLOUT_TIMED_OUT, model_deployment.DEPLOYMENT_FAILED},
+		{model_deployment.DEPLOYMENT_ACTIVE, constants.ROLLOUT_IN_PROGRESS, model_deployment.DEPLOYMENT_ACTIVE},
+		{model_deployment.DEPLOYMENT_ACTIVE, constants.ROLLOUT_SUCCESS, model_deployment.DEPLOYMENT_ACTIVE},
+		{model_deployment.DEPLOYMENT_FAILED, constants.ROLLOUT_SUCCESS, model_deployment.DEPLOYMENT_ACTIVE},
+		{model_deployment.DEPLOYMENT_FAILED, constants.ROLLOUT_PENDING, model_deployment.DEPLOYMENT_FAILED},
+		{model_deployment.DEPLOYMENT_PAUSED, constants.ROLLOUT_FAILED, model_deployment.DEPLOYMENT_PAUSED},
+		{model_deployment.DEPLOYMENT_PAUSED, constants.ROLLOUT_SUCCESS, model_deployment.DEPLOYMENT_PAUSED},
+	} {
+		if got := rolloutLifecycleStatus(tc.status, tc.result); got != tc.want {
+			t.Errorf("rolloutLifecycleStatus(%s, %s): expected %s, got %s", tc.status, tc.result, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package svc
+
+import "fmt"
+
+// ValidationError is a request the service rejects once it is resolved against the stored deployment,
+// e.g. partial resources that are invalid merged with the current ones. The DAO answers it with a 400.
+type ValidationError struct {
+	Field string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid %s. %s", e.Field, e.Err.Error())
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
@@ -19,6 +19,12 @@ func (svc EventLoggerService) LogEvent(payload model_event_logger.Event) ([]map[
 	deployments, err := svc.repository.Kubernetes.ListDeployments("default")
 	var deploymentInfo []map[string]interface{}
 	if err == nil {
+		// Live usage is fetched once for the namespace and split per deployment
+		pods, podsErr := svc.repository.Kubernetes.ListPods("default")
+		if podsErr != nil {
+			logger.Logger.Warn("Error while listing pods", zap.Any(logger.KEY_ERROR, podsErr.Error()))
+		}
+		usage := podsUsage(svc.repository, "default", "")
 
 		for _, d := range deployments {
 			replicasNeeded := int32(0)
@@ -33,9 +39,8 @@ func (svc EventLoggerService) LogEvent(payload model_event_logger.Event) ([]map[
 			// Calculate the age of the deployment
 			age := time.Since(d.CreationTimestamp.Time)
 
-			// Fetch resource utilization if you have metrics access (optional, requires Metrics API)
-			// For example, get memory and CPU usage per pod (implement separately)
-			// resourceUtilization := getDeploymentResourceUtilization(d.Name, namespace, k)
+			// Resource utilization of the deployment's pods, left out when the metrics api is unavailable
+			utilization := resourceUtilization(svc.repository.Metrics.Source(), selectedPods(d, pods), usage)
 
 			// Create YAML manifest string
 			// deploymentYAML, err := yaml.Marshal(d)
@@ -45,14 +50,14 @@ func (svc EventLoggerService) LogEvent(payload model_event_logger.Event) ([]map[
 			// }
 
 			deploymentDetails := map[string]interface{}{
-				"Deployment Name":    d.Name,
-				"Namespace":          d.Namespace,
-				"Replicas Needed":    replicasNeeded,
-				"Replicas Available": replicasAvailable,
-				"Status":             d.Status.Conditions,
-				"Age":                age.String(),
-				"Creation Time":      d.CreationTimestamp.Time,
-				// "Resource Utilization": resourceUtilization, // CPU/Memory data as needed
+				"Deployment Name":      d.Name,
+				"Namespace":            d.Namespace,
+				"Replicas Needed":      replicasNeeded,
+				"Replicas Available":   replicasAvailable,
+				"Status":               d.Status.Conditions,
+				"Age":                  age.String(),
+				"Creation Time":        d.CreationTimestamp.Time,
+				"Resource Utilization": utilization,
 				// "Deployment Manifest": string(deploymentYAML),
 			}
 
@@ -0,0 +1,102 @@
+package svc
+
+import (
+	"context"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// historyEntry is the history record of a change in progress, with the deployment record from before the change
+type historyEntry struct {
+	record model_deployment.HistoryRecord
+	before *model_deployment.CreateDeploymentRequest
+}
+
+// beginHistory starts the history record of a change, it is written by endHistory once the change is done
+func (svc DeploymentService) beginHistory(namespace, deploymentName, action string, actor model_deployment.Actor) *historyEntry {
+	// A deployment without a record yet has nothing to diff against
+	before, err := svc.GetDeploymentFromDBByName(namespace, deploymentName)
+	if err != nil {
+		before = nil
+	}
+	return &historyEntry{
+		record: model_deployment.HistoryRecord{
+			Namespace:      namespace,
+			DeploymentName: deploymentName,
+			Action:         action,
+			Actor:          actor,
+		},
+		before: before,
+	}
+}
+
+// endHistory appends the history record of a change with its outcome and what it changed on the deployment record.
+// A history record that can't be written is logged, the change itself already happened.
+func (svc DeploymentService) endHistory(entry *historyEntry, changeErr error) {
+	after, err := svc.GetDeploymentFromDBByName(entry.record.Namespace, entry.record.DeploymentName)
+	if err != nil {
+		after = nil
+	}
+	// Changes set up front describe what happened outside the record, e.g. a repaired cluster
+	if changes := model_deployment.DiffDeployments(entry.before, after); len(changes) > 0 || entry.record.Changes == nil {
+		entry.record.Changes = changes
+	}
+	entry.record.Outcome = model_deployment.HISTORY_SUCCEEDED
+	if changeErr != nil {
+		entry.record.Outcome = model_deployment.HISTORY_FAILED
+		entry.record.Error = changeErr.Error()
+	}
+	entry.record.CreatedAt = time.Now()
+	if _, err := svc.repository.MongoDB.InsertOne("DEPLOYMENT_HISTORY", entry.record); err != nil {
+		logger.Logger.Error("Error while recording deployment history", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+}
+
+// GetDeploymentHistory returns a page of the history of a deployment, newest first
+func (svc DeploymentService) GetDeploymentHistory(namespace, deploymentName string, query model_deployment.HistoryQuery) (*model_deployment.HistoryPage, error) {
+	filter := bson.M{"namespace": namespace, "deployment_name": deploymentName}
+	// Records are appended in order, the cursor is the id of the last record of the previous page
+	if query.Cursor != "" {
+		cursorID, err := primitive.ObjectIDFromHex(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor %q", query.Cursor)
+		}
+		filter["_id"] = bson.M{"$lt": cursorID}
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = constants.HISTORY_DEFAULT_LIMIT
+	}
+	if limit > constants.HISTORY_MAX_LIMIT {
+		limit = constants.HISTORY_MAX_LIMIT
+	}
+
+	cursor, err := svc.repository.MongoDB.Aggregate("DEPLOYMENT_HISTORY", mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.M{"_id": -1}}},
+		// One more than the page tells whether there is a next page
+		{{Key: "$limit", Value: limit + 1}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of deployment %s: %w", deploymentName, err)
+	}
+	records := []model_deployment.HistoryRecord{}
+	if err := cursor.All(context.TODO(), &records); err != nil {
+		return nil, fmt.Errorf("failed to decode history of deployment %s: %w", deploymentName, err)
+	}
+
+	page := &model_deployment.HistoryPage{Records: records}
+	if len(records) > limit {
+		page.Records = records[:limit]
+		page.NextCursor = records[limit-1].ID.Hex()
+	}
+	return page, nil
+}
@@ -0,0 +1,50 @@
+package svc
+
+import (
+	"deployment-service/logger"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// leaseHolder identifies this replica of the service in the LEASES collection
+var leaseHolder = primitive.NewObjectID().Hex()
+
+// claimLease makes this replica the only one running the pass named by the lease for up to duration.
+// It reports false while another replica holds the lease. The returned release hands the lease back,
+// keeping it until until so other replicas don't repeat a pass that just ran.
+func (svc DeploymentService) claimLease(name string, duration time.Duration) (func(until time.Time), bool) {
+	now := time.Now()
+	res, err := svc.repository.MongoDB.UpdateOne("LEASES", bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": leaseHolder},
+			bson.M{"expiresAt": bson.M{"$lte": now}},
+		},
+	}, bson.M{"$set": bson.M{"holder": leaseHolder, "expiresAt": now.Add(duration)}})
+	if err != nil {
+		logger.Logger.Error("Error while claiming lease", zap.String("lease", name), zap.Any(logger.KEY_ERROR, err.Error()))
+		return nil, false
+	}
+	if res.MatchedCount == 0 {
+		// Only one replica can create a lease that doesn't exist yet
+		_, err := svc.repository.MongoDB.InsertOne("LEASES", bson.M{"_id": name, "holder": leaseHolder, "expiresAt": now.Add(duration)})
+		if err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				logger.Logger.Error("Error while creating lease", zap.String("lease", name), zap.Any(logger.KEY_ERROR, err.Error()))
+			}
+			return nil, false
+		}
+	}
+	release := func(until time.Time) {
+		_, err := svc.repository.MongoDB.UpdateOne("LEASES", bson.M{"_id": name, "holder": leaseHolder},
+			bson.M{"$set": bson.M{"expiresAt": until}})
+		if err != nil {
+			logger.Logger.Warn("Error while releasing lease", zap.String("lease", name), zap.Any(logger.KEY_ERROR, err.Error()))
+		}
+	}
+	return release, true
+}
@@ -0,0 +1,89 @@
+package svc
+
+import (
+	adapter "deployment-service/apps/repository/adapter"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podsUsage fetches the live usage of the pods in a namespace, usage is left out when the metrics api fails
+func podsUsage(repository *adapter.Repository, namespace, labelSelector string) map[string]adapter.PodUsage {
+	usage, err := repository.Metrics.GetPodUsage(namespace, labelSelector)
+	if err != nil {
+		logger.Logger.Warn("Error while fetching pod metrics", zap.Any(logger.KEY_ERROR, err.Error()))
+		return nil
+	}
+	return usage
+}
+
+// resourceUtilization sums the usage of the running pods against their container requests and limits
+func resourceUtilization(source string, pods []corev1.Pod, usage map[string]adapter.PodUsage) *model_deployment.ResourceUtilization {
+	if usage == nil {
+		return nil
+	}
+	var cpuUsage, memoryUsage, cpuRequests, memoryRequests, cpuLimits, memoryLimits resource.Quantity
+	running := 0
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		running++
+		if podUsage, ok := usage[pod.Name]; ok {
+			cpuUsage.Add(podUsage.CPU)
+			memoryUsage.Add(podUsage.Memory)
+		}
+		for _, container := range pod.Spec.Containers {
+			cpuRequests.Add(container.Resources.Requests[corev1.ResourceCPU])
+			memoryRequests.Add(container.Resources.Requests[corev1.ResourceMemory])
+			cpuLimits.Add(container.Resources.Limits[corev1.ResourceCPU])
+			memoryLimits.Add(container.Resources.Limits[corev1.ResourceMemory])
+		}
+	}
+	return &model_deployment.ResourceUtilization{
+		Source: source,
+		Pods:   running,
+		CPU:    resourceUsage(cpuUsage, cpuRequests, cpuLimits),
+		Memory: resourceUsage(memoryUsage, memoryRequests, memoryLimits),
+	}
+}
+
+func resourceUsage(usage, requests, limits resource.Quantity) model_deployment.ResourceUsage {
+	return model_deployment.ResourceUsage{
+		Usage:           usage.String(),
+		Requests:        requests.String(),
+		Limits:          limits.String(),
+		RequestsPercent: usagePercent(usage, requests),
+		LimitsPercent:   usagePercent(usage, limits),
+	}
+}
+
+func usagePercent(usage, total resource.Quantity) *int64 {
+	if total.IsZero() {
+		return nil
+	}
+	percent := usage.MilliValue() * 100 / total.MilliValue()
+	return &percent
+}
+
+// selectedPods filters the pods matched by a deployment's label selector, leaving out the pods of
+// the canary and blue/green deployments sharing its labels
+func selectedPods(deployment appsv1.Deployment, pods []corev1.Pod) []corev1.Pod {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil
+	}
+	var selected []corev1.Pod
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.Labels)) && adapter.PodOwnedBy(&pod, deployment.Name) {
+			selected = append(selected, pod)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,118 @@
+package svc
+
+import (
+	"context"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// operationStep is one step of a create or delete. compensate undoes run and is nil for steps with nothing to undo,
+// both have to be safe to repeat so an abandoned operation can be cleaned up by running them again.
+type operationStep struct {
+	name       string
+	run        func() error
+	compensate func() error
+}
+
+func (svc DeploymentService) recordStep(op *model_deployment.Operation, name, status string, err error) {
+	step := model_deployment.OperationStep{Name: name, Status: status, At: time.Now()}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	op.Steps = append(op.Steps, step)
+	_, updateErr := svc.repository.MongoDB.UpdateOne("OPERATIONS", bson.M{"_id": op.ID}, bson.M{"$push": bson.M{"steps": step}})
+	if updateErr != nil {
+		logger.Logger.Error("Error while recording operation step", zap.String("operation", op.ID.Hex()), zap.Any(logger.KEY_ERROR, updateErr.Error()))
+	}
+}
+
+func (svc DeploymentService) finishOperation(op *model_deployment.Operation, status string, err error) {
+	now := time.Now()
+	op.Status, op.FinishedAt = status, &now
+	fields := bson.M{"status": status, "finishedAt": now}
+	if err != nil {
+		op.Error = err.Error()
+		fields["error"] = op.Error
+	}
+	if _, updateErr := svc.repository.MongoDB.UpdateOne("OPERATIONS", bson.M{"_id": op.ID}, bson.M{"$set": fields}); updateErr != nil {
+		logger.Logger.Error("Error while finishing operation", zap.String("operation", op.ID.Hex()), zap.Any(logger.KEY_ERROR, updateErr.Error()))
+	}
+}
+
+// runOperation runs the steps in order and records each of them. When a step fails the completed steps
+// are compensated in reverse order, the operation ends ROLLED_BACK or FAILED if a compensation failed too.
+func (svc DeploymentService) runOperation(opType, namespace, deploymentName string, steps []operationStep) (*model_deployment.Operation, error) {
+	op := &model_deployment.Operation{
+		ID:             primitive.NewObjectID(),
+		Type:           opType,
+		Namespace:      namespace,
+		DeploymentName: deploymentName,
+		Status:         model_deployment.OPERATION_RUNNING,
+		Steps:          []model_deployment.OperationStep{},
+		StartedAt:      time.Now(),
+	}
+	if _, err := svc.repository.MongoDB.InsertOne("OPERATIONS", op); err != nil {
+		return nil, fmt.Errorf("failed to record %s operation of deployment %s: %w", opType, deploymentName, err)
+	}
+
+	for i, step := range steps {
+		err := step.run()
+		if err == nil {
+			svc.recordStep(op, step.name, model_deployment.STEP_DONE, nil)
+			continue
+		}
+		svc.recordStep(op, step.name, model_deployment.STEP_FAILED, err)
+		status := model_deployment.OPERATION_ROLLED_BACK
+		for j := i - 1; j >= 0; j-- {
+			if steps[j].compensate == nil {
+				continue
+			}
+			if compensateErr := steps[j].compensate(); compensateErr != nil {
+				svc.recordStep(op, steps[j].name, model_deployment.STEP_COMPENSATION_FAILED, compensateErr)
+				status = model_deployment.OPERATION_FAILED
+				continue
+			}
+			svc.recordStep(op, steps[j].name, model_deployment.STEP_COMPENSATED, nil)
+		}
+		err = fmt.Errorf("%s of deployment %s failed at step %s: %w", opType, deploymentName, step.name, err)
+		svc.finishOperation(op, status, err)
+		return op, err
+	}
+	svc.finishOperation(op, model_deployment.OPERATION_SUCCEEDED, nil)
+	return op, nil
+}
+
+// latestOperation returns the most recent operation of a type for a deployment, nil if there is none
+func (svc DeploymentService) latestOperation(opType, namespace, deploymentName string) (*model_deployment.Operation, error) {
+	operations, err := svc.GetDeploymentOperations(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range operations {
+		if operations[i].Type == opType {
+			return &operations[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetDeploymentOperations returns the create and delete operations of a deployment, latest first
+func (svc DeploymentService) GetDeploymentOperations(namespace, deploymentName string) ([]model_deployment.Operation, error) {
+	cursor, err := svc.repository.MongoDB.FindMany("OPERATIONS", bson.M{"namespace": namespace, "deployment_name": deploymentName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operations of deployment %s: %w", deploymentName, err)
+	}
+	operations := []model_deployment.Operation{}
+	if err := cursor.All(context.TODO(), &operations); err != nil {
+		return nil, fmt.Errorf("failed to decode operations of deployment %s: %w", deploymentName, err)
+	}
+	sort.Slice(operations, func(i, j int) bool { return operations[i].StartedAt.After(operations[j].StartedAt) })
+	return operations, nil
+}
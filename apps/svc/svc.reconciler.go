@@ -0,0 +1,382 @@
+package svc
+
+import (
+	"context"
+	adapter "deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	model_tenant "deployment-service/models/model.tenant"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// RunReconciler compares the DEPLOYMENTS records of all tenants with the cluster until ctx is cancelled
+func (svc DeploymentService) RunReconciler(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(constants.RECONCILE_INTERVAL_SECONDS) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		namespaces, err := svc.reconcileNamespaces(ctx)
+		if err != nil {
+			logger.Logger.Error("Error while fetching namespaces to reconcile", zap.Any(logger.KEY_ERROR, err.Error()))
+			continue
+		}
+		for _, namespace := range namespaces {
+			// A namespace is reconciled by one replica at a time, at most once per interval
+			started := time.Now()
+			release, ok := svc.claimLease("reconcile:"+namespace, time.Duration(constants.WORKER_LEASE_SECONDS)*time.Second)
+			if !ok {
+				continue
+			}
+			if _, err := svc.ReconcileNamespace(namespace); err != nil {
+				logger.Logger.Error("Error while reconciling namespace", zap.String("namespace", namespace), zap.Any(logger.KEY_ERROR, err.Error()))
+			}
+			release(started.Add(time.Duration(constants.RECONCILE_INTERVAL_SECONDS) * time.Second / 2))
+		}
+	}
+}
+
+// AdoptDeployments labels the deployments and services of all records as managed by this service,
+// so the cache, which only watches managed objects, also holds the ones created before the label existed
+func (svc DeploymentService) AdoptDeployments(ctx context.Context) error {
+	cursor, err := svc.repository.MongoDB.FindMany("DEPLOYMENTS", bson.M{})
+	if err != nil {
+		return err
+	}
+	var records []model_deployment.CreateDeploymentRequest
+	if err := cursor.All(ctx, &records); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := svc.repository.Kubernetes.AdoptDeployment(record.Namespace, record.Name, record.Name+"-service"); err != nil {
+			logger.Logger.Warn("Error while adopting deployment", zap.String("deployment", record.Name),
+				zap.String("namespace", record.Namespace), zap.Any(logger.KEY_ERROR, err.Error()))
+		}
+	}
+	return nil
+}
+
+// reconcileNamespaces returns the namespaces of all tenants and of all deployment records
+func (svc DeploymentService) reconcileNamespaces(ctx context.Context) ([]string, error) {
+	seen := map[string]bool{}
+	namespaces := []string{}
+	for _, collection := range []string{"TENANTS", "DEPLOYMENTS"} {
+		cursor, err := svc.repository.MongoDB.FindMany(collection, bson.M{})
+		if err != nil {
+			return nil, err
+		}
+		var documents []struct {
+			Namespace string `bson:"namespace"`
+		}
+		if err := cursor.All(ctx, &documents); err != nil {
+			return nil, err
+		}
+		for _, document := range documents {
+			if document.Namespace != "" && !seen[document.Namespace] {
+				seen[document.Namespace] = true
+				namespaces = append(namespaces, document.Namespace)
+			}
+		}
+	}
+	return namespaces, nil
+}
+
+// ReconcileNamespace finds the drift between the deployment records of a namespace and the cluster,
+// repairs it according to the tenant's reconcile policy and keeps the result in the DRIFTS collection
+func (svc DeploymentService) ReconcileNamespace(namespace string) ([]model_deployment.DeploymentDrift, error) {
+	records, err := svc.GetAllDeploymentsFromDBByNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	// Records and untracked deployments are compared with what this service manages, the rest of the namespace is the tenant's
+	deployments, err := svc.repository.Kubernetes.ListManagedDeployments(namespace)
+	if err != nil {
+		return nil, err
+	}
+	services, err := svc.repository.Kubernetes.ListManagedServices(namespace)
+	if err != nil {
+		return nil, err
+	}
+	live := map[string]*appsv1.Deployment{}
+	for i := range deployments {
+		live[deployments[i].Name] = &deployments[i]
+	}
+	serviceNames := map[string]bool{}
+	for _, service := range services {
+		serviceNames[service.Name] = true
+	}
+	policy := (TenantService{svc.repository}).ReconcilePolicy(namespace)
+	busy, err := svc.runningOperations(namespace)
+	if err != nil {
+		return nil, err
+	}
+	// A create, delete or update caught between its cluster and MongoDB writes looks like drift
+	grace := time.Duration(constants.RECONCILE_GRACE_SECONDS) * time.Second
+	inFlight := func(name string, updatedAt time.Time) bool {
+		if busy[name] || time.Since(updatedAt) < grace {
+			return true
+		}
+		return live[name] != nil && changing(live[name], grace)
+	}
+
+	drifts := []model_deployment.DeploymentDrift{}
+	tracked := map[string]bool{}
+	for i := range records {
+		record := &records[i]
+		tracked[record.Name] = true
+		tracked[canaryName(record.Name)] = true
+		tracked[blueGreenName(record.Name, colorBlue)] = true
+		tracked[blueGreenName(record.Name, colorGreen)] = true
+		// A delete in progress removes the resources one by one
+		if record.LifecycleStatus() == model_deployment.DEPLOYMENT_DELETING {
+			continue
+		}
+		if deployment := live[record.Name]; deployment != nil {
+			svc.recordRolloutStatus(record, deployment)
+		}
+		if inFlight(record.Name, record.UpdatedAt) {
+			continue
+		}
+		for _, drift := range detectDrift(record, live[record.Name], serviceNames) {
+			drift.Policy = policy
+			drift.Repair, err = svc.recordRepair(policy, record, live[record.Name], drift)
+			if err != nil {
+				drift.RepairError = err.Error()
+			}
+			drifts = append(drifts, drift)
+			// Without a deployment there is nothing else to compare
+			if drift.Kind == model_deployment.DRIFT_MISSING_DEPLOYMENT {
+				break
+			}
+		}
+	}
+	for _, deployment := range deployments {
+		if deployment.Labels[adapter.ManagedByLabel] != adapter.ManagedByValue || tracked[deployment.Name] || inFlight(deployment.Name, time.Time{}) {
+			continue
+		}
+		drift := model_deployment.DeploymentDrift{
+			Namespace:      namespace,
+			DeploymentName: deployment.Name,
+			Kind:           model_deployment.DRIFT_UNTRACKED_DEPLOYMENT,
+			Actual:         adapter.MainContainer(&deployment.Spec.Template.Spec, deployment.Name).Image,
+			Policy:         policy,
+			DetectedAt:     time.Now(),
+		}
+		drift.Repair, err = svc.recordRepair(policy, nil, &deployment, drift)
+		if err != nil {
+			drift.RepairError = err.Error()
+		}
+		drifts = append(drifts, drift)
+	}
+
+	// Only the latest pass is kept, drift that is gone isn't reported anymore
+	if _, err := svc.repository.MongoDB.DeleteMany("DRIFTS", bson.M{"namespace": namespace}); err != nil {
+		return nil, fmt.Errorf("failed to clear drift of namespace %s: %w", namespace, err)
+	}
+	if len(drifts) > 0 {
+		documents := []interface{}{}
+		for _, drift := range drifts {
+			documents = append(documents, drift)
+		}
+		if _, err := svc.repository.MongoDB.InsertMany("DRIFTS", documents); err != nil {
+			return nil, fmt.Errorf("failed to store drift of namespace %s: %w", namespace, err)
+		}
+	}
+	return drifts, nil
+}
+
+// runningOperations returns the names of the deployments of a namespace with a create or delete in progress.
+// Operations running for longer than OPERATION_STALE_SECONDS were abandoned and don't count.
+func (svc DeploymentService) runningOperations(namespace string) (map[string]bool, error) {
+	cursor, err := svc.repository.MongoDB.FindMany("OPERATIONS", bson.M{
+		"namespace": namespace,
+		"status":    model_deployment.OPERATION_RUNNING,
+		"startedAt": bson.M{"$gt": time.Now().Add(-time.Duration(constants.OPERATION_STALE_SECONDS) * time.Second)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running operations of namespace %s: %w", namespace, err)
+	}
+	var operations []model_deployment.Operation
+	if err := cursor.All(context.TODO(), &operations); err != nil {
+		return nil, fmt.Errorf("failed to decode running operations of namespace %s: %w", namespace, err)
+	}
+	busy := map[string]bool{}
+	for _, op := range operations {
+		busy[op.DeploymentName] = true
+	}
+	return busy, nil
+}
+
+// changing reports whether a deployment was just created or is still rolling out a change
+func changing(deployment *appsv1.Deployment, grace time.Duration) bool {
+	if time.Since(deployment.CreationTimestamp.Time) < grace {
+		return true
+	}
+	result, _ := adapter.DeploymentRolloutResult(deployment)
+	return result == constants.ROLLOUT_PENDING || result == constants.ROLLOUT_IN_PROGRESS
+}
+
+// recordRolloutStatus moves a deployment between ACTIVE and FAILED as its latest rollout fails or succeeds
+func (svc DeploymentService) recordRolloutStatus(record *model_deployment.CreateDeploymentRequest, deployment *appsv1.Deployment) {
+	result, _ := adapter.DeploymentRolloutResult(deployment)
+	status := rolloutLifecycleStatus(record.LifecycleStatus(), result)
+	if status == record.LifecycleStatus() {
+		return
+	}
+	// The status is only changed if no pause or delete got to the record in the meantime,
+	// documents written before the status was tracked have none
+	var currentStatus interface{} = record.Status
+	if record.Status == "" {
+		currentStatus = bson.M{"$in": bson.A{"", nil}}
+	}
+	_, err := svc.repository.MongoDB.UpdateOne("DEPLOYMENTS", bson.M{"namespace": record.Namespace, "name": record.Name, "status": currentStatus},
+		bson.M{"$set": bson.M{"status": status, "updatedAt": time.Now()}})
+	if err != nil {
+		logger.Logger.Warn("Error while updating deployment status", zap.String("deployment", record.Name), zap.Any(logger.KEY_ERROR, err.Error()))
+		return
+	}
+	record.Status = status
+}
+
+// GetDrift returns the drift found by the latest reconcile of a namespace
+func (svc DeploymentService) GetDrift(namespace string) ([]model_deployment.DeploymentDrift, error) {
+	cursor, err := svc.repository.MongoDB.FindMany("DRIFTS", bson.M{"namespace": namespace})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drift of namespace %s: %w", namespace, err)
+	}
+	drifts := []model_deployment.DeploymentDrift{}
+	if err := cursor.All(context.TODO(), &drifts); err != nil {
+		return nil, fmt.Errorf("failed to decode drift of namespace %s: %w", namespace, err)
+	}
+	return drifts, nil
+}
+
+// detectDrift compares a record with its live deployment and service.
+// The image isn't compared during a blue/green release or a canary promotion, the record keeps the stable image
+// until they complete. The replicas aren't compared while an autoscaler owns them.
+func detectDrift(record *model_deployment.CreateDeploymentRequest, deployment *appsv1.Deployment, services map[string]bool) []model_deployment.DeploymentDrift {
+	drifts := []model_deployment.DeploymentDrift{}
+	newDrift := func(kind, expected, actual string) model_deployment.DeploymentDrift {
+		return model_deployment.DeploymentDrift{
+			Namespace:      record.Namespace,
+			DeploymentName: record.Name,
+			Kind:           kind,
+			Expected:       expected,
+			Actual:         actual,
+			DetectedAt:     time.Now(),
+		}
+	}
+	if deployment == nil {
+		return append(drifts, newDrift(model_deployment.DRIFT_MISSING_DEPLOYMENT, record.Image, ""))
+	}
+	promoting := record.Canary != nil && record.Canary.Phase != "" && record.Canary.Phase != model_deployment.CANARY_RUNNING
+	if record.BlueGreen == nil && !promoting {
+		image := adapter.MainContainer(&deployment.Spec.Template.Spec, record.Name).Image
+		if image != record.Image {
+			drifts = append(drifts, newDrift(model_deployment.DRIFT_IMAGE, record.Image, image))
+		}
+	}
+	if record.Autoscaler == nil {
+		if replicas := liveReplicas(deployment); replicas != record.Replicas {
+			drifts = append(drifts, newDrift(model_deployment.DRIFT_REPLICAS, fmt.Sprint(record.Replicas), fmt.Sprint(replicas)))
+		}
+	}
+	if !services[record.Name+"-service"] {
+		drifts = append(drifts, newDrift(model_deployment.DRIFT_MISSING_SERVICE, record.Name+"-service", ""))
+	}
+	return drifts
+}
+
+func liveReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
+
+// recordRepair repairs a drift and records the repair in the deployment history. Repairs of the cluster
+// leave the record as it is, their history has the drift they reverted. The record of a missing deployment
+// is deleted through DeleteDeployment, which records the deletion itself.
+func (svc DeploymentService) recordRepair(policy string, record *model_deployment.CreateDeploymentRequest, deployment *appsv1.Deployment,
+	drift model_deployment.DeploymentDrift) (string, error) {
+	if policy == model_tenant.RECONCILE_DATABASE && drift.Kind == model_deployment.DRIFT_MISSING_DEPLOYMENT {
+		return svc.repairDrift(policy, record, deployment, drift)
+	}
+	history := svc.beginHistory(drift.Namespace, drift.DeploymentName, model_deployment.HISTORY_REPAIR,
+		model_deployment.Actor{Name: model_deployment.ACTOR_RECONCILER})
+	if policy == model_tenant.RECONCILE_CLUSTER {
+		history.record.Changes = []model_deployment.HistoryChange{{Field: driftField(drift.Kind), Before: drift.Actual, After: drift.Expected}}
+	}
+	repair, err := svc.repairDrift(policy, record, deployment, drift)
+	// Drift that is only reported changed nothing
+	if repair != "" {
+		svc.endHistory(history, err)
+	}
+	return repair, err
+}
+
+// driftField is the history field a drift is about
+func driftField(kind string) string {
+	switch kind {
+	case model_deployment.DRIFT_REPLICAS:
+		return "replicas"
+	case model_deployment.DRIFT_MISSING_SERVICE:
+		return "service"
+	}
+	return "image"
+}
+
+// repairDrift applies the reconcile policy to a drift and describes what it did.
+// Drift that has no counterpart in the source of truth, like a missing service under the DATABASE policy, is only reported.
+func (svc DeploymentService) repairDrift(policy string, record *model_deployment.CreateDeploymentRequest, deployment *appsv1.Deployment,
+	drift model_deployment.DeploymentDrift) (string, error) {
+	namespace, deploymentName := drift.Namespace, drift.DeploymentName
+	switch policy {
+	case model_tenant.RECONCILE_CLUSTER:
+		switch drift.Kind {
+		case model_deployment.DRIFT_MISSING_DEPLOYMENT:
+			options, err := deploymentOptions(record)
+			if err != nil {
+				return "", err
+			}
+			err = svc.repository.Kubernetes.CreateDeployment(namespace, deploymentName, record.Image, record.Replicas, record.ContainerPort, options)
+			return "recreated deployment", err
+		case model_deployment.DRIFT_MISSING_SERVICE:
+			return "recreated service", svc.exposeDeployment(namespace, deploymentName, record.ContainerPort, recordExposure(record))
+		case model_deployment.DRIFT_IMAGE:
+			_, err := svc.repository.Kubernetes.UpdateDeploymentSpec(namespace, deploymentName, func(d *appsv1.Deployment) error {
+				adapter.MainContainer(&d.Spec.Template.Spec, deploymentName).Image = record.Image
+				return nil
+			})
+			return "set image to " + record.Image, err
+		case model_deployment.DRIFT_REPLICAS:
+			return fmt.Sprintf("scaled to %d replicas", record.Replicas), svc.scaleDeployment(namespace, deploymentName, record.Replicas)
+		case model_deployment.DRIFT_UNTRACKED_DEPLOYMENT:
+			return "deleted deployment", svc.repository.Kubernetes.DeleteDeployment(namespace, deploymentName)
+		}
+	case model_tenant.RECONCILE_DATABASE:
+		switch drift.Kind {
+		case model_deployment.DRIFT_MISSING_DEPLOYMENT:
+			_, err := svc.DeleteDeployment(namespace, deploymentName, record.RepoScoutId, model_deployment.Actor{Name: model_deployment.ACTOR_RECONCILER})
+			return "deleted record", err
+		case model_deployment.DRIFT_IMAGE:
+			_, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"image": drift.Actual})
+			return "recorded image " + drift.Actual, err
+		case model_deployment.DRIFT_REPLICAS:
+			replicas := liveReplicas(deployment)
+			_, err := svc.updateDeploymentInMongoDB(namespace, deploymentName, bson.M{"replicas": replicas})
+			return fmt.Sprintf("recorded %d replicas", replicas), err
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,148 @@
+package svc
+
+import (
+	"testing"
+	"time"
+
+	model_deployment "deployment-service/models/model.deployment"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func liveDeployment(name, image string, replicas int32) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant"}}
+	deployment.Spec.Replicas = ptr.To(replicas)
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: name, Image: image}, {Name: "envoy", Image: "envoy:1"}}
+	return deployment
+}
+
+func driftKinds(drifts []model_deployment.DeploymentDrift) []string {
+	kinds := []string{}
+	for _, drift := range drifts {
+		kinds = append(kinds, drift.Kind)
+	}
+	return kinds
+}
+
+func TestDetectDrift(t *testing.T) {
+	record := func() *model_deployment.CreateDeploymentRequest {
+		return &model_deployment.CreateDeploymentRequest{Name: "web", Namespace: "tenant", Image: "nginx:1", Replicas: 2}
+	}
+	withService := map[string]bool{"web-service": true}
+
+	for _, tc := range []struct {
+		name       string
+		record     *model_deployment.CreateDeploymentRequest
+		deployment *appsv1.Deployment
+		services   map[string]bool
+		want       []string
+	}{
+		{"in sync", record(), liveDeployment("web", "nginx:1", 2), withService, []string{}},
+		{"missing deployment", record(), nil, withService, []string{model_deployment.DRIFT_MISSING_DEPLOYMENT}},
+		{"missing service", record(), liveDeployment("web", "nginx:1", 2), map[string]bool{}, []string{model_deployment.DRIFT_MISSING_SERVICE}},
+		{"image and replicas", record(), liveDeployment("web", "nginx:2", 5), withService,
+			[]string{model_deployment.DRIFT_IMAGE, model_deployment.DRIFT_REPLICAS}},
+		{
+			"replicas owned by an autoscaler",
+			func() *model_deployment.CreateDeploymentRequest {
+				r := record()
+				r.Autoscaler = &model_deployment.AutoscalerConfig{MinReplicas: 1, MaxReplicas: 10}
+				return r
+			}(),
+			liveDeployment("web", "nginx:1", 7), withService, []string{},
+		},
+		{
+			"image during a blue/green release",
+			func() *model_deployment.CreateDeploymentRequest {
+				r := record()
+				r.BlueGreen = &model_deployment.BlueGreenState{}
+				return r
+			}(),
+			liveDeployment("web", "nginx:2", 2), withService, []string{},
+		},
+		{
+			"image during a canary promotion",
+			func() *model_deployment.CreateDeploymentRequest {
+				r := record()
+				r.Canary = &model_deployment.CanaryState{Phase: model_deployment.CANARY_PROMOTION_FAILED, Image: "nginx:2"}
+				return r
+			}(),
+			liveDeployment("web", "nginx:2", 2), withService, []string{},
+		},
+		{
+			"image next to a running canary",
+			func() *model_deployment.CreateDeploymentRequest {
+				r := record()
+				r.Canary = &model_deployment.CanaryState{Phase: model_deployment.CANARY_RUNNING, Image: "nginx:2"}
+				return r
+			}(),
+			liveDeployment("web", "nginx:2", 2), withService, []string{model_deployment.DRIFT_IMAGE},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			drifts := detectDrift(tc.record, tc.deployment, tc.services)
+			kinds := driftKinds(drifts)
+			if len(kinds) != len(tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, kinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tc.want[i] {
+					t.Errorf("expected %v, got %v", tc.want, kinds)
+				}
+			}
+			for _, drift := range drifts {
+				if drift.Namespace != "tenant" || drift.DeploymentName != "web" {
+					t.Errorf("expected the drift to name the deployment, got %+v", drift)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectDriftValues(t *testing.T) {
+	record := &model_deployment.CreateDeploymentRequest{Name: "web", Namespace: "tenant", Image: "nginx:1", Replicas: 2}
+	drifts := detectDrift(record, liveDeployment("web", "nginx:2", 5), map[string]bool{"web-service": true})
+	if len(drifts) != 2 {
+		t.Fatalf("expected 2 drifts, got %+v", drifts)
+	}
+	if drifts[0].Expected != "nginx:1" || drifts[0].Actual != "nginx:2" {
+		t.Errorf("expected the image to drift from nginx:1 to nginx:2, got %+v", drifts[0])
+	}
+	if drifts[1].Expected != "2" || drifts[1].Actual != "5" {
+		t.Errorf("expected the replicas to drift from 2 to 5, got %+v", drifts[1])
+	}
+}
+
+func TestChanging(t *testing.T) {
+	settled := func() *appsv1.Deployment {
+		deployment := liveDeployment("web", "nginx:1", 2)
+		deployment.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		deployment.Generation, deployment.Status.ObservedGeneration = 3, 3
+		deployment.Status.Replicas, deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas = 2, 2, 2
+		return deployment
+	}
+	if changing(settled(), time.Minute) {
+		t.Error("expected a settled deployment not to be changing")
+	}
+
+	created := settled()
+	created.CreationTimestamp = metav1.NewTime(time.Now())
+	if !changing(created, time.Minute) {
+		t.Error("expected a deployment created within the grace period to be changing")
+	}
+
+	pending := settled()
+	pending.Generation = 4
+	if !changing(pending, time.Minute) {
+		t.Error("expected a deployment with an unobserved spec to be changing")
+	}
+
+	rolling := settled()
+	rolling.Status.UpdatedReplicas = 1
+	if !changing(rolling, time.Minute) {
+		t.Error("expected a deployment with a rollout in progress to be changing")
+	}
+}
@@ -0,0 +1,174 @@
+package svc
+
+import (
+	"context"
+	model_deployment "deployment-service/models/model.deployment"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func scheduleFilter(namespace, deploymentName, scheduleID string) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule id %s: %w", scheduleID, err)
+	}
+	return bson.M{"_id": id, "namespace": namespace, "deployment_name": deploymentName}, nil
+}
+
+func withNextRun(schedule *model_deployment.Schedule) {
+	if !schedule.Enabled {
+		return
+	}
+	runs, err := schedule.NextRuns(time.Now(), 1)
+	if err == nil && len(runs) > 0 {
+		schedule.NextRunAt = &runs[0]
+	}
+}
+
+// CreateSchedule adds a replica schedule to a deployment, the scheduler picks it up on its next sync
+func (svc DeploymentService) CreateSchedule(namespace, deploymentName string, payload model_deployment.ScheduleReq) (*model_deployment.Schedule, error) {
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := svc.GetDeploymentFromDBByName(namespace, deploymentName); err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", deploymentName, namespace, err)
+	}
+	enabled := true
+	if payload.Enabled != nil {
+		enabled = *payload.Enabled
+	}
+	now := time.Now()
+	schedule := model_deployment.Schedule{
+		ID:             primitive.NewObjectID(),
+		Namespace:      namespace,
+		DeploymentName: deploymentName,
+		Cron:           payload.Cron,
+		Timezone:       payload.Timezone,
+		Replicas:       payload.Replicas,
+		Enabled:        enabled,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if _, err := svc.repository.MongoDB.InsertOne("SCHEDULES", schedule); err != nil {
+		return nil, fmt.Errorf("failed to insert schedule for deployment %s: %w", deploymentName, err)
+	}
+	withNextRun(&schedule)
+	return &schedule, nil
+}
+
+// GetSchedules lists the replica schedules of a deployment with their next run
+func (svc DeploymentService) GetSchedules(namespace, deploymentName string) ([]model_deployment.Schedule, error) {
+	cursor, err := svc.repository.MongoDB.FindMany("SCHEDULES", bson.M{"namespace": namespace, "deployment_name": deploymentName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules of deployment %s: %w", deploymentName, err)
+	}
+	schedules := []model_deployment.Schedule{}
+	if err := cursor.All(context.TODO(), &schedules); err != nil {
+		return nil, fmt.Errorf("failed to decode schedules of deployment %s: %w", deploymentName, err)
+	}
+	for i := range schedules {
+		withNextRun(&schedules[i])
+	}
+	return schedules, nil
+}
+
+// GetSchedule returns a single replica schedule of a deployment
+func (svc DeploymentService) GetSchedule(namespace, deploymentName, scheduleID string) (*model_deployment.Schedule, error) {
+	filter, err := scheduleFilter(namespace, deploymentName, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	var schedule model_deployment.Schedule
+	if err := svc.repository.MongoDB.FindOne("SCHEDULES", filter).Decode(&schedule); err != nil {
+		return nil, fmt.Errorf("failed to get schedule %s of deployment %s: %w", scheduleID, deploymentName, err)
+	}
+	withNextRun(&schedule)
+	return &schedule, nil
+}
+
+// UpdateSchedule replaces the cron, timezone, replicas and enabled flag of a schedule
+func (svc DeploymentService) UpdateSchedule(namespace, deploymentName, scheduleID string, payload model_deployment.ScheduleReq) (*model_deployment.Schedule, error) {
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	filter, err := scheduleFilter(namespace, deploymentName, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	fields := bson.M{
+		"cron":      payload.Cron,
+		"timezone":  payload.Timezone,
+		"replicas":  payload.Replicas,
+		"updatedAt": time.Now(),
+	}
+	if payload.Enabled != nil {
+		fields["enabled"] = *payload.Enabled
+	}
+	result, err := svc.repository.MongoDB.UpdateOne("SCHEDULES", filter, bson.M{"$set": fields})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update schedule %s of deployment %s: %w", scheduleID, deploymentName, err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("schedule %s of deployment %s not found", scheduleID, deploymentName)
+	}
+	return svc.GetSchedule(namespace, deploymentName, scheduleID)
+}
+
+// DeleteSchedule removes a schedule, its run history is kept for auditing
+func (svc DeploymentService) DeleteSchedule(namespace, deploymentName, scheduleID string) error {
+	filter, err := scheduleFilter(namespace, deploymentName, scheduleID)
+	if err != nil {
+		return err
+	}
+	result, err := svc.repository.MongoDB.DeleteOne("SCHEDULES", filter)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule %s of deployment %s: %w", scheduleID, deploymentName, err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("schedule %s of deployment %s not found", scheduleID, deploymentName)
+	}
+	return nil
+}
+
+// GetScheduleNextRuns previews the next count times a schedule fires
+func (svc DeploymentService) GetScheduleNextRuns(namespace, deploymentName, scheduleID string, count int) ([]time.Time, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be positive")
+	}
+	schedule, err := svc.GetSchedule(namespace, deploymentName, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.NextRuns(time.Now(), count)
+}
+
+// GetScheduleRuns returns the audit entries of a schedule, latest first
+func (svc DeploymentService) GetScheduleRuns(namespace, deploymentName, scheduleID string) ([]model_deployment.ScheduleRun, error) {
+	filter, err := scheduleFilter(namespace, deploymentName, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := svc.repository.MongoDB.FindMany("SCHEDULE_RUNS", bson.M{
+		"schedule_id": filter["_id"], "namespace": namespace, "deployment_name": deploymentName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runs of schedule %s: %w", scheduleID, err)
+	}
+	runs := []model_deployment.ScheduleRun{}
+	if err := cursor.All(context.TODO(), &runs); err != nil {
+		return nil, fmt.Errorf("failed to decode runs of schedule %s: %w", scheduleID, err)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].FiredAt.After(runs[j].FiredAt) })
+	return runs, nil
+}
+
+// deleteSchedules removes the schedules of a deleted deployment
+func (svc DeploymentService) deleteSchedules(namespace, deploymentName string) error {
+	_, err := svc.repository.MongoDB.DeleteMany("SCHEDULES", bson.M{"namespace": namespace, "deployment_name": deploymentName})
+	return err
+}
@@ -0,0 +1,137 @@
+package svc
+
+import (
+	"context"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	"errors"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type scheduleEntry struct {
+	entryID   cron.EntryID
+	updatedAt time.Time
+}
+
+// RunScheduler fires the enabled replica schedules of all tenants until ctx is cancelled.
+// Schedules are reloaded from MongoDB on every sync, so changes made through the API apply within one interval.
+func (svc DeploymentService) RunScheduler(ctx context.Context) {
+	runner := cron.New(cron.WithLocation(time.UTC))
+	entries := map[primitive.ObjectID]scheduleEntry{}
+	svc.syncSchedules(runner, entries)
+	runner.Start()
+	defer runner.Stop()
+
+	ticker := time.NewTicker(time.Duration(constants.SCHEDULE_SYNC_INTERVAL_SECONDS) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			svc.syncSchedules(runner, entries)
+		}
+	}
+}
+
+// syncSchedules adds new schedules to the runner, replaces changed ones and drops deleted or disabled ones
+func (svc DeploymentService) syncSchedules(runner *cron.Cron, entries map[primitive.ObjectID]scheduleEntry) {
+	cursor, err := svc.repository.MongoDB.FindMany("SCHEDULES", bson.M{"enabled": true})
+	if err != nil {
+		logger.Logger.Error("Error while fetching schedules", zap.Any(logger.KEY_ERROR, err.Error()))
+		return
+	}
+	var schedules []model_deployment.Schedule
+	if err := cursor.All(context.TODO(), &schedules); err != nil {
+		logger.Logger.Error("Error while decoding schedules", zap.Any(logger.KEY_ERROR, err.Error()))
+		return
+	}
+
+	active := map[primitive.ObjectID]bool{}
+	for _, schedule := range schedules {
+		active[schedule.ID] = true
+		entry, ok := entries[schedule.ID]
+		if ok && entry.updatedAt.Equal(schedule.UpdatedAt) {
+			continue
+		}
+		if ok {
+			runner.Remove(entry.entryID)
+			delete(entries, schedule.ID)
+		}
+		parsed, err := model_deployment.ParseSchedule(schedule.Cron, schedule.Timezone)
+		if err != nil {
+			logger.Logger.Error("Skipping invalid schedule", zap.String("schedule", schedule.ID.Hex()), zap.Any(logger.KEY_ERROR, err.Error()))
+			continue
+		}
+		id := schedule.ID
+		entryID := runner.Schedule(parsed, cron.FuncJob(func() { svc.fireSchedule(id) }))
+		entries[schedule.ID] = scheduleEntry{entryID: entryID, updatedAt: schedule.UpdatedAt}
+	}
+	for id, entry := range entries {
+		if !active[id] {
+			runner.Remove(entry.entryID)
+			delete(entries, id)
+		}
+	}
+}
+
+// fireSchedule scales the deployment through the regular update flow and records the outcome.
+// Every replica of the service runs the scheduler, the first one to claim a firing in MongoDB applies it.
+// The claim also reads the schedule again, so one deleted or disabled since the last sync doesn't fire.
+func (svc DeploymentService) fireSchedule(id primitive.ObjectID) {
+	// Cron expressions have a granularity of one minute, which identifies the firing across replicas
+	firedAt := time.Now().UTC().Truncate(time.Minute)
+	var schedule model_deployment.Schedule
+	err := svc.repository.MongoDB.FindOneAndUpdate("SCHEDULES", bson.M{
+		"_id":     id,
+		"enabled": true,
+		"$or": bson.A{
+			bson.M{"lastRunAt": bson.M{"$exists": false}},
+			bson.M{"lastRunAt": bson.M{"$lt": firedAt}},
+		},
+	}, bson.M{"$set": bson.M{"lastRunAt": firedAt}}).Decode(&schedule)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Logger.Error("Error while claiming schedule", zap.String("schedule", id.Hex()), zap.Any(logger.KEY_ERROR, err.Error()))
+		}
+		return
+	}
+	run := model_deployment.ScheduleRun{
+		ScheduleID:     schedule.ID,
+		Namespace:      schedule.Namespace,
+		DeploymentName: schedule.DeploymentName,
+		Cron:           schedule.Cron,
+		Replicas:       schedule.Replicas,
+		Outcome:        model_deployment.SCHEDULE_RUN_SUCCESS,
+		FiredAt:        firedAt,
+	}
+	_, err = svc.UpdateDeploymentByName(schedule.Namespace, &model_deployment.UpdateDeploymentReq{
+		Name:     schedule.DeploymentName,
+		Replicas: schedule.Replicas,
+	}, model_deployment.Actor{Name: model_deployment.ACTOR_SCHEDULER})
+	if err != nil {
+		run.Outcome = model_deployment.SCHEDULE_RUN_FAILED
+		run.Error = err.Error()
+		logger.Logger.Error("Error while applying schedule", zap.String("schedule", schedule.ID.Hex()),
+			zap.String("deployment", schedule.DeploymentName), zap.String("namespace", schedule.Namespace), zap.Any(logger.KEY_ERROR, err.Error()))
+	} else {
+		logger.Logger.Info("Applied schedule", zap.String("schedule", schedule.ID.Hex()),
+			zap.String("deployment", schedule.DeploymentName), zap.Int32("replicas", schedule.Replicas))
+	}
+
+	if _, err := svc.repository.MongoDB.InsertOne("SCHEDULE_RUNS", run); err != nil {
+		logger.Logger.Error("Error while recording schedule run", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+	_, err = svc.repository.MongoDB.UpdateOne("SCHEDULES", bson.M{"_id": schedule.ID},
+		bson.M{"$set": bson.M{"last_outcome": run.Outcome}})
+	if err != nil {
+		logger.Logger.Error("Error while updating schedule", zap.Any(logger.KEY_ERROR, err.Error()))
+	}
+}
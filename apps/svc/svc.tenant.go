@@ -0,0 +1,245 @@
+package svc
+
+import (
+	"context"
+	adapter "deployment-service/apps/repository/adapter"
+	"deployment-service/constants"
+	"deployment-service/logger"
+	model_deployment "deployment-service/models/model.deployment"
+	model_tenant "deployment-service/models/model.tenant"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	tenantQuotaName      = "tenant-quota"
+	tenantLimitRangeName = "tenant-limits"
+)
+
+type TenantService struct {
+	repository *adapter.Repository
+}
+
+// GetTenant fetches the tenant record of a namespace from the TENANTS collection
+func (svc TenantService) GetTenant(namespace string) (*model_tenant.Tenant, error) {
+	var tenant = model_tenant.Tenant{}
+	if err := svc.repository.MongoDB.FindOne("TENANTS", bson.M{"namespace": namespace}).Decode(&tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// BootstrapNamespace applies the tenant plan and network isolation to a namespace without a tenant record.
+// The record is only inserted once everything is applied, so a bootstrap that failed halfway is completed by
+// the next call; the apply steps create or update. A namespace this service recreated gets the limits stored
+// for its tenant again. Namespaces that ran deployments before tenants were introduced are left alone, their
+// workloads could exceed the default plan; they are migrated explicitly through UpdateTenantQuota.
+func (svc TenantService) BootstrapNamespace(namespace string, created bool) error {
+	tenant, err := svc.GetTenant(namespace)
+	if err == nil {
+		if !created {
+			return nil
+		}
+		if err := svc.applyTenantLimits(tenant); err != nil {
+			return err
+		}
+		return svc.applyNetworkIsolation(namespace)
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to get tenant %s: %w", namespace, err)
+	}
+	if !created {
+		count, err := svc.repository.MongoDB.CountDocuments("DEPLOYMENTS", bson.M{"namespace": namespace})
+		if err != nil {
+			return fmt.Errorf("failed to count deployments of %s: %w", namespace, err)
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+	tenant, err = newTenant(namespace)
+	if err != nil {
+		return err
+	}
+	if err := svc.applyTenantLimits(tenant); err != nil {
+		return err
+	}
+	if err := svc.applyNetworkIsolation(namespace); err != nil {
+		return err
+	}
+	if _, err := svc.repository.MongoDB.InsertOne("TENANTS", tenant); err != nil {
+		logger.Logger.Error("Error while inserting new tenant", zap.Any(logger.KEY_ERROR, err.Error()))
+		return err
+	}
+	return nil
+}
+
+// newTenant builds the tenant record of a namespace on the default plan
+func newTenant(namespace string) (*model_tenant.Tenant, error) {
+	plan, ok := model_tenant.TENANT_PLANS[constants.DEFAULT_TENANT_PLAN]
+	if !ok {
+		return nil, fmt.Errorf("default tenant plan %s is not defined", constants.DEFAULT_TENANT_PLAN)
+	}
+	return &model_tenant.Tenant{
+		Namespace:  namespace,
+		Plan:       constants.DEFAULT_TENANT_PLAN,
+		Quota:      plan.Quota,
+		LimitRange: plan.LimitRange,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+// applyTenantLimits applies the ResourceQuota and LimitRange of a tenant to its namespace
+func (svc TenantService) applyTenantLimits(tenant *model_tenant.Tenant) error {
+	hard, err := tenant.Quota.Hard()
+	if err != nil {
+		return err
+	}
+	containerLimits, err := tenant.LimitRange.ContainerLimits()
+	if err != nil {
+		return err
+	}
+	if err := svc.repository.Kubernetes.ApplyResourceQuota(tenant.Namespace, tenantQuotaName, hard); err != nil {
+		return err
+	}
+	return svc.repository.Kubernetes.ApplyLimitRange(tenant.Namespace, tenantLimitRangeName, containerLimits)
+}
+
+// applyNetworkIsolation keeps other tenants out of the namespace
+func (svc TenantService) applyNetworkIsolation(namespace string) error {
+	return svc.repository.Kubernetes.ApplyDefaultNetworkPolicies(namespace, constants.INGRESS_CONTROLLER_NAMESPACE)
+}
+
+// isolateExistingNamespace isolates a namespace that already runs deployments. Their network access
+// policies are applied first, so publicly exposed and shared services stay reachable once ingress is denied.
+func (svc TenantService) isolateExistingNamespace(namespace string) error {
+	cursor, err := svc.repository.MongoDB.FindMany("DEPLOYMENTS", bson.M{"namespace": namespace})
+	if err != nil {
+		return fmt.Errorf("failed to get deployments of %s: %w", namespace, err)
+	}
+	var deployments []model_deployment.CreateDeploymentRequest
+	if err := cursor.All(context.TODO(), &deployments); err != nil {
+		return fmt.Errorf("failed to decode deployments of %s: %w", namespace, err)
+	}
+	deploymentService := DeploymentService{svc.repository}
+	for _, deployment := range deployments {
+		err := deploymentService.applyNetworkAccess(namespace, deployment.Name, deployment.ContainerPort, deployment.Exposure, deployment.NetworkAccess)
+		if err != nil {
+			return fmt.Errorf("failed to apply network access of deployment %s: %w", deployment.Name, err)
+		}
+	}
+	return svc.applyNetworkIsolation(namespace)
+}
+
+// UpdateTenantQuota moves a tenant to another plan and/or overrides its quota and container defaults.
+// A namespace without a tenant record is migrated: it starts from the default plan, gets isolated
+// and the record is created.
+func (svc TenantService) UpdateTenantQuota(namespace string, payload model_tenant.UpdateTenantQuotaReq) (*model_tenant.Tenant, error) {
+	tenant, err := svc.GetTenant(namespace)
+	migrating := errors.Is(err, mongo.ErrNoDocuments)
+	if migrating {
+		tenant, err = newTenant(namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant %s: %w", namespace, err)
+	}
+	if payload.Plan != "" {
+		plan, ok := model_tenant.TENANT_PLANS[payload.Plan]
+		if !ok {
+			return nil, fmt.Errorf("unknown tenant plan %s", payload.Plan)
+		}
+		tenant.Plan = payload.Plan
+		tenant.Quota = plan.Quota
+		tenant.LimitRange = plan.LimitRange
+	}
+	if payload.Quota != nil {
+		tenant.Quota = tenant.Quota.Merge(*payload.Quota)
+	}
+	if payload.LimitRange != nil {
+		tenant.LimitRange = tenant.LimitRange.Merge(*payload.LimitRange)
+	}
+
+	if err := svc.applyTenantLimits(tenant); err != nil {
+		return nil, err
+	}
+	if migrating {
+		if err := svc.isolateExistingNamespace(namespace); err != nil {
+			return nil, err
+		}
+		if _, err := svc.repository.MongoDB.InsertOne("TENANTS", tenant); err != nil {
+			return nil, fmt.Errorf("failed to create tenant %s: %w", namespace, err)
+		}
+		return tenant, nil
+	}
+	tenant.UpdatedAt = time.Now()
+	_, err = svc.repository.MongoDB.UpdateOne("TENANTS", bson.M{"namespace": namespace}, bson.M{
+		"$set": bson.M{
+			"plan":        tenant.Plan,
+			"quota":       tenant.Quota,
+			"limit_range": tenant.LimitRange,
+			"updatedAt":   tenant.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tenant %s: %w", namespace, err)
+	}
+	return tenant, nil
+}
+
+// ReconcilePolicy returns the reconcile policy of a tenant, tenants without one get DEFAULT_RECONCILE_POLICY
+func (svc TenantService) ReconcilePolicy(namespace string) string {
+	tenant, err := svc.GetTenant(namespace)
+	if err != nil || tenant.ReconcilePolicy == "" {
+		return constants.DEFAULT_RECONCILE_POLICY
+	}
+	return tenant.ReconcilePolicy
+}
+
+// UpdateReconcilePolicy sets what the reconciler does with the drift of a tenant's deployments
+func (svc TenantService) UpdateReconcilePolicy(namespace string, payload model_tenant.UpdateReconcilePolicyReq) (*model_tenant.Tenant, error) {
+	if err := model_tenant.ValidateReconcilePolicy(payload.Policy); err != nil {
+		return nil, err
+	}
+	tenant, err := svc.GetTenant(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant %s: %w", namespace, err)
+	}
+	tenant.ReconcilePolicy = payload.Policy
+	tenant.UpdatedAt = time.Now()
+	_, err = svc.repository.MongoDB.UpdateOne("TENANTS", bson.M{"namespace": namespace}, bson.M{
+		"$set": bson.M{
+			"reconcile_policy": tenant.ReconcilePolicy,
+			"updatedAt":        tenant.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tenant %s: %w", namespace, err)
+	}
+	return tenant, nil
+}
+
+// GetTenantQuotaUsage reports the used and hard limits of the tenant ResourceQuota
+func (svc TenantService) GetTenantQuotaUsage(namespace string) ([]model_tenant.TenantQuotaUsage, error) {
+	quota, err := svc.repository.Kubernetes.GetResourceQuota(namespace, tenantQuotaName)
+	if err != nil {
+		return nil, err
+	}
+	var usage = []model_tenant.TenantQuotaUsage{}
+	for name, hard := range quota.Status.Hard {
+		used := quota.Status.Used[name]
+		usage = append(usage, model_tenant.TenantQuotaUsage{
+			Resource: string(name),
+			Used:     used.String(),
+			Hard:     hard.String(),
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Resource < usage[j].Resource })
+	return usage, nil
+}
@@ -0,0 +1,106 @@
+package constants
+
+// rollout results reported by the rollout status api
+const (
+	ROLLOUT_PENDING     string = "PENDING"
+	ROLLOUT_IN_PROGRESS string = "IN_PROGRESS"
+	ROLLOUT_SUCCESS     string = "SUCCESS"
+	ROLLOUT_FAILED      string = "FAILED"
+	ROLLOUT_TIMED_OUT   string = "TIMED_OUT"
+)
+
+var (
+	ROLLOUT_REVISION_WAIT_SECONDS int = GetEnvInt("ROLLOUT_REVISION_WAIT_SECONDS", 5)
+)
+
+// default container resources for deployments that don't specify their own
+var (
+	DEFAULT_REQUEST_CPU    string = GetEnvString("DEFAULT_REQUEST_CPU", "50m")
+	DEFAULT_REQUEST_MEMORY string = GetEnvString("DEFAULT_REQUEST_MEMORY", "0.2Gi")
+	DEFAULT_LIMIT_CPU      string = GetEnvString("DEFAULT_LIMIT_CPU", "0.5")
+	DEFAULT_LIMIT_MEMORY   string = GetEnvString("DEFAULT_LIMIT_MEMORY", "0.5Gi")
+)
+
+// service exposure for deployments that don't choose one
+var (
+	DEFAULT_EXPOSURE_TYPE string = GetEnvString("DEFAULT_EXPOSURE_TYPE", "LoadBalancer")
+	DEFAULT_SERVICE_PORT  int    = GetEnvInt("DEFAULT_SERVICE_PORT", 80)
+	INGRESS_CLASS_NAME    string = GetEnvString("INGRESS_CLASS_NAME", "")
+)
+
+// tail applied to deployment logs when the caller doesn't follow or ask for a tail
+var (
+	LOGS_DEFAULT_TAIL_LINES int = GetEnvInt("LOGS_DEFAULT_TAIL_LINES", 100)
+	LOGS_MAX_LINE_BYTES     int = GetEnvInt("LOGS_MAX_LINE_BYTES", 1024*1024)
+)
+
+// page sizes of the events api
+var (
+	EVENTS_DEFAULT_LIMIT int = GetEnvInt("EVENTS_DEFAULT_LIMIT", 10)
+	EVENTS_MAX_LIMIT     int = GetEnvInt("EVENTS_MAX_LIMIT", 100)
+)
+
+// plan applied to tenant namespaces when they are created
+var (
+	DEFAULT_TENANT_PLAN string = GetEnvString("DEFAULT_TENANT_PLAN", "free")
+)
+
+// namespace of the ingress controller, tenant network policies let it reach every pod
+var (
+	INGRESS_CONTROLLER_NAMESPACE string = GetEnvString("INGRESS_CONTROLLER_NAMESPACE", "ingress-nginx")
+)
+
+// blue/green deployments keep the previous color for the retention window, the worker checks them on every interval
+var (
+	BLUE_GREEN_RETENTION_SECONDS      int = GetEnvInt("BLUE_GREEN_RETENTION_SECONDS", 3600)
+	BLUE_GREEN_CHECK_INTERVAL_SECONDS int = GetEnvInt("BLUE_GREEN_CHECK_INTERVAL_SECONDS", 15)
+)
+
+// the scheduler reloads the scaling schedules from MongoDB on every sync interval
+var (
+	SCHEDULE_SYNC_INTERVAL_SECONDS int = GetEnvInt("SCHEDULE_SYNC_INTERVAL_SECONDS", 30)
+	SCHEDULE_PREVIEW_DEFAULT_RUNS  int = GetEnvInt("SCHEDULE_PREVIEW_DEFAULT_RUNS", 5)
+	SCHEDULE_PREVIEW_MAX_RUNS      int = GetEnvInt("SCHEDULE_PREVIEW_MAX_RUNS", 50)
+)
+
+// the reconciler compares the DEPLOYMENTS records with the cluster on every interval,
+// tenants without a reconcile policy of their own get the default one.
+// Deployments written within the grace period may be in the middle of an update and are left for the next pass.
+var (
+	RECONCILE_INTERVAL_SECONDS int    = GetEnvInt("RECONCILE_INTERVAL_SECONDS", 60)
+	DEFAULT_RECONCILE_POLICY   string = GetEnvString("DEFAULT_RECONCILE_POLICY", "REPORT")
+	RECONCILE_GRACE_SECONDS    int    = GetEnvInt("RECONCILE_GRACE_SECONDS", 120)
+)
+
+// deployments created without probes only get tcp checks on their container port when enabled,
+// workloads that listen late or on another port would otherwise be restarted or never become ready
+var (
+	DEFAULT_PROBES_ENABLED bool = GetEnvBool("DEFAULT_PROBES_ENABLED", false)
+)
+
+// replicas of the service take turns on the background passes through leases in the LEASES collection,
+// a replica that stopped in the middle of a pass has its lease taken over after this long
+var (
+	WORKER_LEASE_SECONDS int = GetEnvInt("WORKER_LEASE_SECONDS", 300)
+)
+
+// a create still running after this long is taken as abandoned, e.g. by a restart, and cleaned up by the next attempt
+var (
+	OPERATION_STALE_SECONDS int = GetEnvInt("OPERATION_STALE_SECONDS", 300)
+)
+
+// deployment and service reads are served from informers kept in sync by watches,
+// a full relist runs on every resync and reads go to the api server until the first sync completes.
+// A write waits up to KUBERNETES_CACHE_WAIT_SECONDS for the watch to bring its result into the cache.
+var (
+	KUBERNETES_CACHE_ENABLED              bool = GetEnvBool("KUBERNETES_CACHE_ENABLED", true)
+	KUBERNETES_CACHE_RESYNC_SECONDS       int  = GetEnvInt("KUBERNETES_CACHE_RESYNC_SECONDS", 600)
+	KUBERNETES_CACHE_SYNC_TIMEOUT_SECONDS int  = GetEnvInt("KUBERNETES_CACHE_SYNC_TIMEOUT_SECONDS", 120)
+	KUBERNETES_CACHE_WAIT_SECONDS         int  = GetEnvInt("KUBERNETES_CACHE_WAIT_SECONDS", 5)
+)
+
+// page sizes of the deployment history api
+var (
+	HISTORY_DEFAULT_LIMIT int = GetEnvInt("HISTORY_DEFAULT_LIMIT", 20)
+	HISTORY_MAX_LIMIT     int = GetEnvInt("HISTORY_MAX_LIMIT", 100)
+)
@@ -37,3 +37,8 @@ var (
 	MONGODB_PWD  string = GetEnvString("MONGODB_PWD", "xx")
 	MONGODB_NAME string = GetEnvString("MONGODB_NAME", "Cluster0")
 )
+
+// metrics source for resource utilization: metrics-server, fake, or auto to detect metrics-server
+var (
+	METRICS_PROVIDER string = GetEnvString("METRICS_PROVIDER", "auto")
+)
@@ -4,6 +4,7 @@ import (
 	"deployment-service/apps/repository/adapter"
 	"deployment-service/apps/repository/instance"
 	"deployment-service/apps/routes"
+	"deployment-service/apps/svc"
 	"deployment-service/constants"
 
 	"context"
@@ -26,7 +27,8 @@ func main() {
 	// PSqlConnection := instance.GetPSqlConnection()
 	MongoDBConnection := instance.GetMongoConnection()
 	KubernetesConnection := instance.GetKubernetesConnection()
-	repository := adapter.RepositoryAdapter(MongoDBConnection, KubernetesConnection)
+	MetricsProvider := instance.GetMetricsProvider(KubernetesConnection)
+	repository := adapter.RepositoryAdapter(MongoDBConnection, KubernetesConnection, MetricsProvider)
 
 	fmt.Println("Starting %s API server", "deployment-service")
 
@@ -47,6 +49,23 @@ func main() {
 	// queue := svc.NewServiceRepo(repository).SQSService
 	// go queue.InitSQS()
 
+	// Background workers stop with the server
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	if constants.KUBERNETES_CACHE_ENABLED {
+		go func() {
+			if err := svc.NewServiceRepo(repository).DeploymentService.AdoptDeployments(workerCtx); err != nil {
+				fmt.Printf("Error while adopting existing deployments: %v\n", err)
+			}
+			if err := repository.Kubernetes.StartCache(workerCtx); err != nil {
+				fmt.Printf("Kubernetes cache unavailable, reading from the api server: %v\n", err)
+			}
+		}()
+	}
+	go svc.NewServiceRepo(repository).DeploymentService.RunReleaseWorker(workerCtx)
+	go svc.NewServiceRepo(repository).DeploymentService.RunScheduler(workerCtx)
+	go svc.NewServiceRepo(repository).DeploymentService.RunReconciler(workerCtx)
+
 	quit := make(chan os.Signal)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -58,6 +77,7 @@ func main() {
 
 	// Shutdown server
 	fmt.Println("Shutting down server.")
+	stopWorkers()
 	if err := server.Shutdown(ctx); err != nil {
 		fmt.Println("Server forced to shutdown: %v\n", err)
 	}
@@ -36,6 +36,12 @@ func ValidateHeaderSecrets(repository *adapter.Repository) gin.HandlerFunc {
 		// }
 		username := c.Request.Header.Get("username")
 		c.Set("username", username)
+		// Changes are recorded for the caller named in x-actor, or for the tenant without it
+		actor := c.Request.Header.Get("x-actor")
+		if actor == "" {
+			actor = username
+		}
+		c.Set("actor", actor)
 		c.Next()
 	}
 }
@@ -80,6 +86,12 @@ func ValidateJWT(repository *adapter.Repository) gin.HandlerFunc {
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
 			if username, exists := claims["username"].(string); exists {
 				c.Set("username", username)
+				// Changes are recorded for the subject of the token, or for the tenant without one
+				actor := username
+				if subject, ok := claims["sub"].(string); ok && subject != "" {
+					actor = subject
+				}
+				c.Set("actor", actor)
 			} else {
 				c.JSON(http.StatusUnauthorized, gin.H{"error": "Username not found in token"})
 				c.Abort()
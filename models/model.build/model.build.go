@@ -1,6 +1,8 @@
 package model_build
 
 import (
+	model_deployment "deployment-service/models/model.deployment"
+	model_tenant "deployment-service/models/model.tenant"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -36,11 +38,13 @@ type GetRepoScoutResp struct {
 }
 
 type TenantResourceResp struct {
-	KubernetesVersion string `json:"kubernetes_version"`
-	NoOfPods          int64  `json:"no_of_pods"`
-	NoOfDeployments   int64  `json:"no_of_deployments"`
-	NoOfServices      int64  `json:"no_of_services"`
-	TenantUsername    string `json:"tenant_username"`
+	KubernetesVersion string                                `json:"kubernetes_version"`
+	NoOfPods          int64                                 `json:"no_of_pods"`
+	NoOfDeployments   int64                                 `json:"no_of_deployments"`
+	NoOfServices      int64                                 `json:"no_of_services"`
+	TenantUsername    string                                `json:"tenant_username"`
+	Quota             []model_tenant.TenantQuotaUsage       `json:"quota"`
+	Usage             *model_deployment.ResourceUtilization `json:"usage,omitempty"`
 }
 
 // RepoReleases holds information about a repository and its top releases
@@ -0,0 +1,17 @@
+package model_config
+
+import "time"
+
+// TenantConfigReq creates or replaces a tenant managed secret or config map
+type TenantConfigReq struct {
+	Name string            `json:"name"`
+	Data map[string]string `json:"data"`
+}
+
+// TenantConfigInfo describes a tenant managed secret or config map.
+// Only the keys are listed so secret values are never echoed back.
+type TenantConfigInfo struct {
+	Name      string    `json:"name"`
+	Keys      []string  `json:"keys"`
+	CreatedAt time.Time `json:"created_at"`
+}
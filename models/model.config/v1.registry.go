@@ -0,0 +1,20 @@
+package model_config
+
+import "time"
+
+// RegistryCredentialReq creates or replaces the pull secret of a private registry
+type RegistryCredentialReq struct {
+	Name     string `json:"name"`
+	Server   string `json:"server"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+}
+
+// RegistryCredentialInfo describes a registry pull secret, the password is never echoed back
+type RegistryCredentialInfo struct {
+	Name      string    `json:"name"`
+	Server    string    `json:"server"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package model_deployment
+
+import (
+	"errors"
+	"time"
+)
+
+// AutoscalerConfig configures the HorizontalPodAutoscaler of a deployment, utilization targets are percentages of the requests
+type AutoscalerConfig struct {
+	MinReplicas             int32  `bson:"min_replicas" json:"min_replicas"`
+	MaxReplicas             int32  `bson:"max_replicas" json:"max_replicas"`
+	TargetCPUUtilization    *int32 `bson:"target_cpu_utilization,omitempty" json:"target_cpu_utilization,omitempty"`
+	TargetMemoryUtilization *int32 `bson:"target_memory_utilization,omitempty" json:"target_memory_utilization,omitempty"`
+}
+
+func (c AutoscalerConfig) Validate() error {
+	if c.MinReplicas < 1 {
+		return errors.New("min_replicas must be at least 1")
+	}
+	if c.MaxReplicas < c.MinReplicas {
+		return errors.New("max_replicas must be greater than or equal to min_replicas")
+	}
+	if c.TargetCPUUtilization == nil && c.TargetMemoryUtilization == nil {
+		return errors.New("at least one of target_cpu_utilization or target_memory_utilization is required")
+	}
+	for _, target := range []*int32{c.TargetCPUUtilization, c.TargetMemoryUtilization} {
+		if target != nil && *target <= 0 {
+			return errors.New("utilization targets must be greater than zero")
+		}
+	}
+	return nil
+}
+
+type AutoscalerStatus struct {
+	Config                   AutoscalerConfig `json:"config"`
+	CurrentReplicas          int32            `json:"current_replicas"`
+	DesiredReplicas          int32            `json:"desired_replicas"`
+	CurrentCPUUtilization    *int32           `json:"current_cpu_utilization,omitempty"`
+	CurrentMemoryUtilization *int32           `json:"current_memory_utilization,omitempty"`
+	LastScaleTime            *time.Time       `json:"last_scale_time,omitempty"`
+}
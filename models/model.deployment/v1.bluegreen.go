@@ -0,0 +1,35 @@
+package model_deployment
+
+import "time"
+
+// phases of a blue/green release
+const (
+	// the stable pods are relabelled with their color, the service is pinned to it once they all carry it
+	BLUE_GREEN_PREPARING string = "PREPARING"
+	// the new color is starting, the service still selects the stable color
+	BLUE_GREEN_PENDING string = "PENDING"
+	// the service selects the new color, the stable color is kept for switching back
+	BLUE_GREEN_SWITCHED string = "SWITCHED"
+	// the retention window is over, the deployment rolls to the new color and the parallel deployment is removed
+	BLUE_GREEN_RETIRING string = "RETIRING"
+)
+
+// BlueGreenUpdate turns an image update of the main container into a blue/green release.
+// RetentionSeconds is how long the previous color is kept after the switch, it defaults to BLUE_GREEN_RETENTION_SECONDS.
+type BlueGreenUpdate struct {
+	RetentionSeconds int64 `json:"retention_seconds"`
+}
+
+// BlueGreenState is the blue/green release of a deployment, kept on its DEPLOYMENTS document until it is retired or switched back
+type BlueGreenState struct {
+	Phase            string     `bson:"phase" json:"phase"`
+	StableColor      string     `bson:"stable_color" json:"stable_color"`
+	NewColor         string     `bson:"new_color" json:"new_color"`
+	Image            string     `bson:"image" json:"image"`
+	PreviousImage    string     `bson:"previous_image" json:"previous_image"`
+	RetentionSeconds int64      `bson:"retention_seconds" json:"retention_seconds"`
+	Generation       int64      `bson:"generation,omitempty" json:"generation,omitempty"`
+	StartedAt        time.Time  `bson:"startedAt" json:"startedAt"`
+	SwitchedAt       *time.Time `bson:"switchedAt,omitempty" json:"switchedAt,omitempty"`
+	RetainUntil      *time.Time `bson:"retainUntil,omitempty" json:"retainUntil,omitempty"`
+}
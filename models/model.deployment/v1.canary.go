@@ -0,0 +1,39 @@
+package model_deployment
+
+import "time"
+
+const (
+	// the canary serves next to the stable deployment
+	CANARY_RUNNING string = "RUNNING"
+	// the stable deployment rolls to the canary image, the canary is removed once the rollout succeeded
+	CANARY_PROMOTING string = "PROMOTING"
+	// the stable deployment failed to roll out the canary image, the canary keeps serving until it is
+	// aborted, which rolls the stable deployment back to its image from before the promotion
+	CANARY_PROMOTION_FAILED string = "PROMOTION_FAILED"
+)
+
+// CanaryUpdate turns an image update of the main container into a canary release of Replicas pods
+type CanaryUpdate struct {
+	Replicas int32 `json:"replicas"`
+}
+
+// CanaryState is the canary release of a deployment, kept on its DEPLOYMENTS document until it is promoted or aborted
+type CanaryState struct {
+	Phase     string    `bson:"phase,omitempty" json:"phase,omitempty"`
+	Image     string    `bson:"image" json:"image"`
+	Replicas  int32     `bson:"replicas" json:"replicas"`
+	StartedAt time.Time `bson:"startedAt" json:"startedAt"`
+	// Generation is the stable deployment generation rolling out the canary image while promoting
+	Generation int64 `bson:"generation,omitempty" json:"generation,omitempty"`
+	// StableImage is the image of the stable deployment before the promotion, an abort rolls back to it
+	StableImage string `bson:"stable_image,omitempty" json:"stable_image,omitempty"`
+}
+
+// CanaryStatus compares the canary with the stable deployment, Weight is the canary's share of the ready pods in percent
+type CanaryStatus struct {
+	CanaryState
+	StableImage         string `json:"stable_image"`
+	StableReadyReplicas int32  `json:"stable_ready_replicas"`
+	CanaryReadyReplicas int32  `json:"canary_ready_replicas"`
+	Weight              int32  `json:"weight"`
+}
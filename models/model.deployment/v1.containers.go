@@ -0,0 +1,162 @@
+package model_deployment
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Container is a sidecar or init container running next to the deployment's main container.
+// The main container keeps its name, image and port on the deployment itself.
+type Container struct {
+	Name          string               `bson:"name" json:"name"`
+	Image         string               `bson:"image" json:"image"`
+	ContainerPort int32                `bson:"container_port,omitempty" json:"container_port,omitempty"`
+	Command       []string             `bson:"command,omitempty" json:"command,omitempty"`
+	Args          []string             `bson:"args,omitempty" json:"args,omitempty"`
+	Resources     *DeploymentResources `bson:"resources,omitempty" json:"resources,omitempty"`
+	Env           []EnvVar             `bson:"env,omitempty" json:"env,omitempty"`
+	Probes        *DeploymentProbes    `bson:"probes,omitempty" json:"probes,omitempty"`
+}
+
+// ContainerInfo lists a container of a running deployment
+type ContainerInfo struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	Init  bool   `json:"init"`
+}
+
+// Validate checks a single container, init containers run to completion and can't have probes
+func (c Container) Validate(init bool) error {
+	if errs := validation.IsDNS1123Label(c.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid container name %q: %s", c.Name, strings.Join(errs, ", "))
+	}
+	if c.Image == "" {
+		return fmt.Errorf("container %s needs an image", c.Name)
+	}
+	if c.ContainerPort < 0 || c.ContainerPort > 65535 {
+		return fmt.Errorf("container port %d of container %s is out of range", c.ContainerPort, c.Name)
+	}
+	if c.Resources != nil {
+		if _, err := c.Resources.ResourceRequirements(); err != nil {
+			return fmt.Errorf("container %s: %w", c.Name, err)
+		}
+	}
+	if err := ValidateEnv(c.Env); err != nil {
+		return fmt.Errorf("container %s: %w", c.Name, err)
+	}
+	if c.Probes != nil {
+		if init {
+			return fmt.Errorf("init container %s can't have probes", c.Name)
+		}
+		if err := c.Probes.WithDefaults(c.ContainerPort).Validate(); err != nil {
+			return fmt.Errorf("container %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateContainers checks the sidecars and init containers of a deployment,
+// container names must be unique within the pod and differ from the main container.
+func ValidateContainers(mainContainer string, sidecars, initContainers []Container) error {
+	names := map[string]bool{mainContainer: true}
+	for _, group := range []struct {
+		containers []Container
+		init       bool
+	}{{sidecars, false}, {initContainers, true}} {
+		for _, container := range group.containers {
+			if err := container.Validate(group.init); err != nil {
+				return err
+			}
+			if names[container.Name] {
+				return fmt.Errorf("container name %s is used more than once", container.Name)
+			}
+			names[container.Name] = true
+		}
+	}
+	return nil
+}
+
+// KubernetesContainer converts the container to its kubernetes spec.
+// Containers without resources get the namespace LimitRange defaults.
+func (c Container) KubernetesContainer() (corev1.Container, error) {
+	container := corev1.Container{
+		Name:    c.Name,
+		Image:   c.Image,
+		Command: c.Command,
+		Args:    c.Args,
+		Env:     EnvVars(c.Env),
+	}
+	if c.ContainerPort != 0 {
+		container.Ports = []corev1.ContainerPort{{ContainerPort: c.ContainerPort}}
+	}
+	if c.Resources != nil {
+		requirements, err := c.Resources.ResourceRequirements()
+		if err != nil {
+			return container, fmt.Errorf("container %s: %w", c.Name, err)
+		}
+		container.Resources = requirements
+	}
+	if c.Probes != nil {
+		probes := c.Probes.WithDefaults(c.ContainerPort)
+		container.LivenessProbe = probes.Liveness.KubernetesProbe()
+		container.ReadinessProbe = probes.Readiness.KubernetesProbe()
+		container.StartupProbe = probes.Startup.KubernetesProbe()
+	}
+	return container, nil
+}
+
+// KubernetesContainers converts a list of containers to their kubernetes specs
+func KubernetesContainers(containers []Container) ([]corev1.Container, error) {
+	var k8sContainers []corev1.Container
+	for _, c := range containers {
+		container, err := c.KubernetesContainer()
+		if err != nil {
+			return nil, err
+		}
+		k8sContainers = append(k8sContainers, container)
+	}
+	return k8sContainers, nil
+}
+
+// ContainersFromKubernetes converts kubernetes containers back to the deployment containers, skipping the main container
+func ContainersFromKubernetes(k8sContainers []corev1.Container, mainContainer string) []Container {
+	var containers []Container
+	for _, k8sContainer := range k8sContainers {
+		if k8sContainer.Name == mainContainer {
+			continue
+		}
+		container := Container{
+			Name:    k8sContainer.Name,
+			Image:   k8sContainer.Image,
+			Command: k8sContainer.Command,
+			Args:    k8sContainer.Args,
+			Env:     EnvFromEnvVars(k8sContainer.Env),
+		}
+		if len(k8sContainer.Ports) > 0 {
+			container.ContainerPort = k8sContainer.Ports[0].ContainerPort
+		}
+		if len(k8sContainer.Resources.Requests) > 0 || len(k8sContainer.Resources.Limits) > 0 {
+			resources := ResourcesFromRequirements(k8sContainer.Resources)
+			container.Resources = &resources
+		}
+		probes := ProbesFromContainer(k8sContainer)
+		if probes.Liveness != nil || probes.Readiness != nil || probes.Startup != nil {
+			container.Probes = &probes
+		}
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+// FindContainer returns the index of the named container, or -1
+func FindContainer(containers []Container, name string) int {
+	for i, container := range containers {
+		if container.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -7,22 +7,56 @@ import (
 )
 
 type CreateDeploymentRequest struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name          string             `bson:"name" json:"name"`
-	Namespace     string             `bson:"namespace" json:"namespace"`
-	ContainerPort int32              `bson:"containerPort" json:"container_port"`
-	Image         string             `bson:"image" json:"image"`
-	Replicas      int32              `bson:"replicas" json:"replicas"`
-	RepoScoutId   string             `bson:"repo_scout_id" json:"repo_scout_id"`
-	Status        string             `bson:"status" json:"status"`
-	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID               primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name             string               `bson:"name" json:"name"`
+	Namespace        string               `bson:"namespace" json:"namespace"`
+	ContainerPort    int32                `bson:"containerPort" json:"container_port"`
+	Image            string               `bson:"image" json:"image"`
+	Replicas         int32                `bson:"replicas" json:"replicas"`
+	Resources        *DeploymentResources `bson:"resources,omitempty" json:"resources,omitempty"`
+	Env              []EnvVar             `bson:"env,omitempty" json:"env,omitempty"`
+	Probes           *DeploymentProbes    `bson:"probes,omitempty" json:"probes,omitempty"`
+	Sidecars         []Container          `bson:"sidecars,omitempty" json:"sidecars,omitempty"`
+	InitContainers   []Container          `bson:"init_containers,omitempty" json:"init_containers,omitempty"`
+	ImagePullSecrets []string             `bson:"image_pull_secrets,omitempty" json:"image_pull_secrets"`
+	Exposure         *DeploymentExposure  `bson:"exposure,omitempty" json:"exposure,omitempty"`
+	Autoscaler       *AutoscalerConfig    `bson:"autoscaler,omitempty" json:"autoscaler,omitempty"`
+	NetworkAccess    []NetworkAccessRule  `bson:"network_access,omitempty" json:"network_access,omitempty"`
+	Canary           *CanaryState         `bson:"canary,omitempty" json:"canary,omitempty"`
+	BlueGreen        *BlueGreenState      `bson:"blue_green,omitempty" json:"blue_green,omitempty"`
+	RepoScoutId      string               `bson:"repo_scout_id" json:"repo_scout_id"`
+	Status           string               `bson:"status" json:"status"`
+	Paused           *PausedState         `bson:"paused,omitempty" json:"paused,omitempty"`
+	Revision         int64                `bson:"revision" json:"revision"`
+	Generation       int64                `bson:"generation" json:"generation"`
+	RestartedAt      *time.Time           `bson:"restartedAt,omitempty" json:"restartedAt,omitempty"`
+	CreatedAt        time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time            `bson:"updatedAt" json:"updatedAt"`
 }
 
+// UpdateDeploymentReq updates the main container, or the sidecar or init container named by Container
 type UpdateDeploymentReq struct {
-	Name     string `json:"name"`
-	Replicas int32  `json:"replicas"`
-	Image    string `json:"image"`
+	Name             string               `json:"name"`
+	Container        string               `json:"container"`
+	Replicas         int32                `json:"replicas"`
+	Image            string               `json:"image"`
+	Resources        *DeploymentResources `json:"resources"`
+	Env              []EnvVar             `json:"env"`
+	Probes           *DeploymentProbes    `json:"probes"`
+	ImagePullSecrets *[]string            `json:"image_pull_secrets"`
+	NetworkAccess    []NetworkAccessRule  `json:"network_access"`
+	Canary           *CanaryUpdate        `json:"canary"`
+	BlueGreen        *BlueGreenUpdate     `json:"blue_green"`
+}
+
+// ScaleOnly reports whether the update changes nothing but the replica count
+func (req *UpdateDeploymentReq) ScaleOnly() bool {
+	return req.Container == "" && req.Image == "" && req.Resources == nil && req.Env == nil && req.Probes == nil &&
+		req.ImagePullSecrets == nil && req.NetworkAccess == nil && req.Canary == nil && req.BlueGreen == nil
+}
+
+type RollbackDeploymentReq struct {
+	Revision int64 `json:"revision"`
 }
 
 type DeploymentInfo struct {
@@ -33,6 +67,27 @@ type DeploymentInfo struct {
 	CurrentReplicas   int                    `json:"current_replicas"`
 	Image             string                 `json:"image"`
 	AvailableReplicas int                    `json:"available_replicas"`
+	Env               []EnvVar               `json:"env"`
+	Probes            DeploymentProbes       `json:"probes"`
+	Containers        []ContainerInfo        `json:"containers"`
+	Usage             *ResourceUtilization   `json:"usage,omitempty"`
 	OtherInfo         map[string]interface{} `json:"other_info"`
 	OutOfSync         bool                   `json:"out_of_sync"`
 }
+
+type RolloutStatus struct {
+	DeploymentName     string `json:"deployment_name"`
+	Revision           int64  `json:"revision"`
+	RecordedRevision   int64  `json:"recorded_revision"`
+	Generation         int64  `json:"generation"`
+	ObservedGeneration int64  `json:"observed_generation"`
+	DesiredReplicas    int32  `json:"desired_replicas"`
+	UpdatedReplicas    int32  `json:"updated_replicas"`
+	ReadyReplicas      int32  `json:"ready_replicas"`
+	AvailableReplicas  int32  `json:"available_replicas"`
+	ProgressingReason  string `json:"progressing_reason"`
+	RestartedAt        string `json:"restarted_at,omitempty"`
+	Status             string `json:"status"`
+	Result             string `json:"result"`
+	Message            string `json:"message"`
+}
@@ -0,0 +1,27 @@
+package model_deployment
+
+import "time"
+
+// kinds of drift between a DEPLOYMENTS record and the cluster
+const (
+	DRIFT_MISSING_DEPLOYMENT string = "MISSING_DEPLOYMENT"
+	DRIFT_MISSING_SERVICE    string = "MISSING_SERVICE"
+	DRIFT_IMAGE              string = "IMAGE"
+	DRIFT_REPLICAS           string = "REPLICAS"
+	// a deployment created by the service without a DEPLOYMENTS record, e.g. left behind by a failed create
+	DRIFT_UNTRACKED_DEPLOYMENT string = "UNTRACKED_DEPLOYMENT"
+)
+
+// DeploymentDrift is a difference found by the reconciler, the drift of the latest pass is kept in the DRIFTS collection.
+// Repair describes what the tenant's reconcile policy did about it, it is empty when the drift was only reported.
+type DeploymentDrift struct {
+	Namespace      string    `bson:"namespace" json:"namespace"`
+	DeploymentName string    `bson:"deployment_name" json:"deployment_name"`
+	Kind           string    `bson:"kind" json:"kind"`
+	Expected       string    `bson:"expected,omitempty" json:"expected,omitempty"`
+	Actual         string    `bson:"actual,omitempty" json:"actual,omitempty"`
+	Policy         string    `bson:"policy" json:"policy"`
+	Repair         string    `bson:"repair,omitempty" json:"repair,omitempty"`
+	RepairError    string    `bson:"repair_error,omitempty" json:"repair_error,omitempty"`
+	DetectedAt     time.Time `bson:"detectedAt" json:"detectedAt"`
+}
@@ -0,0 +1,101 @@
+package model_deployment
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// EnvVar is either a plain value or a reference to a key of a tenant managed secret or config map.
+// Secret values are never stored on the deployment, only the reference is.
+type EnvVar struct {
+	Name         string     `bson:"name" json:"name"`
+	Value        string     `bson:"value,omitempty" json:"value,omitempty"`
+	SecretRef    *EnvVarRef `bson:"secret_ref,omitempty" json:"secret_ref,omitempty"`
+	ConfigMapRef *EnvVarRef `bson:"config_map_ref,omitempty" json:"config_map_ref,omitempty"`
+}
+
+type EnvVarRef struct {
+	Name string `bson:"name" json:"name"`
+	Key  string `bson:"key" json:"key"`
+}
+
+// ValidateEnv checks the env var names and that each one has exactly one source
+func ValidateEnv(env []EnvVar) error {
+	seen := map[string]bool{}
+	for _, e := range env {
+		if errs := validation.IsEnvVarName(e.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid env var name %q: %s", e.Name, strings.Join(errs, ", "))
+		}
+		if seen[e.Name] {
+			return fmt.Errorf("duplicate env var %q", e.Name)
+		}
+		seen[e.Name] = true
+
+		sources := 0
+		if e.Value != "" {
+			sources++
+		}
+		for _, ref := range []*EnvVarRef{e.SecretRef, e.ConfigMapRef} {
+			if ref == nil {
+				continue
+			}
+			sources++
+			if ref.Name == "" || ref.Key == "" {
+				return fmt.Errorf("env var %q must reference both a name and a key", e.Name)
+			}
+		}
+		if sources > 1 {
+			return fmt.Errorf("env var %q must have only one of value, secret_ref or config_map_ref", e.Name)
+		}
+	}
+	return nil
+}
+
+// EnvVars converts the env to the kubernetes container env
+func EnvVars(env []EnvVar) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	for _, e := range env {
+		envVar := corev1.EnvVar{Name: e.Name, Value: e.Value}
+		if e.SecretRef != nil {
+			envVar.Value = ""
+			envVar.ValueFrom = &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: e.SecretRef.Name},
+					Key:                  e.SecretRef.Key,
+				},
+			}
+		} else if e.ConfigMapRef != nil {
+			envVar.Value = ""
+			envVar.ValueFrom = &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: e.ConfigMapRef.Name},
+					Key:                  e.ConfigMapRef.Key,
+				},
+			}
+		}
+		envVars = append(envVars, envVar)
+	}
+	return envVars
+}
+
+// EnvFromEnvVars converts a kubernetes container env back to the deployment env.
+// Secret references are returned as references, secret values are never resolved.
+func EnvFromEnvVars(envVars []corev1.EnvVar) []EnvVar {
+	env := []EnvVar{}
+	for _, envVar := range envVars {
+		e := EnvVar{Name: envVar.Name, Value: envVar.Value}
+		if envVar.ValueFrom != nil {
+			if ref := envVar.ValueFrom.SecretKeyRef; ref != nil {
+				e.SecretRef = &EnvVarRef{Name: ref.Name, Key: ref.Key}
+			}
+			if ref := envVar.ValueFrom.ConfigMapKeyRef; ref != nil {
+				e.ConfigMapRef = &EnvVarRef{Name: ref.Name, Key: ref.Key}
+			}
+		}
+		env = append(env, e)
+	}
+	return env
+}
@@ -0,0 +1,33 @@
+package model_deployment
+
+import "time"
+
+// EventsQuery holds the query parameters of the events api
+type EventsQuery struct {
+	Deployment string `form:"deployment"`
+	Type       string `form:"type"`
+	Since      string `form:"since"`
+	Limit      int    `form:"limit"`
+	Cursor     string `form:"cursor"`
+}
+
+type EventObject struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type Event struct {
+	Name           string      `json:"name"`
+	InvolvedObject EventObject `json:"involved_object"`
+	Type           string      `json:"type"`
+	Reason         string      `json:"reason"`
+	Message        string      `json:"message"`
+	Count          int32       `json:"count"`
+	FirstTimestamp time.Time   `json:"first_timestamp"`
+	LastTimestamp  time.Time   `json:"last_timestamp"`
+}
+
+type EventsPage struct {
+	Events     []Event `json:"events"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
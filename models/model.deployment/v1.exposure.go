@@ -0,0 +1,95 @@
+package model_deployment
+
+import (
+	"deployment-service/constants"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	EXPOSURE_CLUSTER_IP    string = "ClusterIP"
+	EXPOSURE_NODE_PORT     string = "NodePort"
+	EXPOSURE_LOAD_BALANCER string = "LoadBalancer"
+	EXPOSURE_INGRESS       string = "Ingress"
+)
+
+// DeploymentExposure decides how the deployment's service is reachable.
+// Ingress exposure publishes a ClusterIP service through an ingress on the given host and path.
+type DeploymentExposure struct {
+	Type        string           `bson:"type" json:"type"`
+	ServicePort int32            `bson:"service_port" json:"service_port"`
+	Ingress     *IngressExposure `bson:"ingress,omitempty" json:"ingress,omitempty"`
+}
+
+type IngressExposure struct {
+	Host string `bson:"host" json:"host"`
+	Path string `bson:"path" json:"path"`
+}
+
+// WithDefaults fills the exposure type, service port and ingress path when they are not set
+func (e DeploymentExposure) WithDefaults() DeploymentExposure {
+	if e.Type == "" {
+		e.Type = constants.DEFAULT_EXPOSURE_TYPE
+		if e.Ingress != nil {
+			e.Type = EXPOSURE_INGRESS
+		}
+	}
+	if e.ServicePort == 0 {
+		e.ServicePort = int32(constants.DEFAULT_SERVICE_PORT)
+	}
+	if e.Type == EXPOSURE_INGRESS {
+		ingress := IngressExposure{}
+		if e.Ingress != nil {
+			ingress = *e.Ingress
+		}
+		if ingress.Path == "" {
+			ingress.Path = "/"
+		}
+		e.Ingress = &ingress
+	}
+	return e
+}
+
+// Validate checks the exposure type, port and ingress settings
+func (e DeploymentExposure) Validate() error {
+	switch e.Type {
+	case "", EXPOSURE_CLUSTER_IP, EXPOSURE_NODE_PORT, EXPOSURE_LOAD_BALANCER:
+		if e.Ingress != nil && e.Type != "" {
+			return fmt.Errorf("ingress can only be set for exposure type %s", EXPOSURE_INGRESS)
+		}
+	case EXPOSURE_INGRESS:
+	default:
+		return fmt.Errorf("exposure type must be one of %s, %s, %s or %s",
+			EXPOSURE_CLUSTER_IP, EXPOSURE_NODE_PORT, EXPOSURE_LOAD_BALANCER, EXPOSURE_INGRESS)
+	}
+	if e.ServicePort < 0 || e.ServicePort > 65535 {
+		return fmt.Errorf("service port %d is out of range", e.ServicePort)
+	}
+	if e.Ingress != nil {
+		if e.Ingress.Host != "" {
+			if errs := validation.IsDNS1123Subdomain(e.Ingress.Host); len(errs) > 0 {
+				return fmt.Errorf("invalid ingress host %q: %s", e.Ingress.Host, strings.Join(errs, ", "))
+			}
+		}
+		if e.Ingress.Path != "" && !strings.HasPrefix(e.Ingress.Path, "/") {
+			return errors.New("ingress path must start with /")
+		}
+	}
+	return nil
+}
+
+// ServiceType is the kubernetes service type backing the exposure
+func (e DeploymentExposure) ServiceType() corev1.ServiceType {
+	switch e.Type {
+	case EXPOSURE_CLUSTER_IP, EXPOSURE_INGRESS:
+		return corev1.ServiceTypeClusterIP
+	case EXPOSURE_NODE_PORT:
+		return corev1.ServiceTypeNodePort
+	default:
+		return corev1.ServiceTypeLoadBalancer
+	}
+}
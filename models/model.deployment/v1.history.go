@@ -0,0 +1,146 @@
+package model_deployment
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// changes recorded in the deployment history
+const (
+	HISTORY_CREATE   string = "CREATE"
+	HISTORY_UPDATE   string = "UPDATE"
+	HISTORY_SCALE    string = "SCALE"
+	HISTORY_RESTART  string = "RESTART"
+	HISTORY_ROLLBACK string = "ROLLBACK"
+	HISTORY_DELETE   string = "DELETE"
+	// a canary or blue/green image became the deployment's image
+	HISTORY_PROMOTE string = "PROMOTE"
+	// the autoscaler of the deployment was created, changed or removed
+	HISTORY_AUTOSCALE string = "AUTOSCALE"
+	// the reconciler repaired drift between the record and the cluster
+	HISTORY_REPAIR string = "REPAIR"
+)
+
+// outcomes of a recorded change
+const (
+	HISTORY_SUCCEEDED string = "SUCCEEDED"
+	HISTORY_FAILED    string = "FAILED"
+)
+
+// actors of the changes the service makes on its own
+const (
+	ACTOR_SCHEDULER      string = "system:scheduler"
+	ACTOR_RECONCILER     string = "system:reconciler"
+	ACTOR_RELEASE_WORKER string = "system:release-worker"
+)
+
+// Actor is who made a change and the request it came in
+type Actor struct {
+	Name      string `bson:"name" json:"name"`
+	SourceIP  string `bson:"source_ip,omitempty" json:"source_ip,omitempty"`
+	RequestID string `bson:"request_id,omitempty" json:"request_id,omitempty"`
+}
+
+// HistoryChange is a field a change set from Before to After, an empty value is a field that wasn't set
+type HistoryChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before string `bson:"before" json:"before"`
+	After  string `bson:"after" json:"after"`
+}
+
+// HistoryRecord is an entry of the DEPLOYMENT_HISTORY collection, written once and never updated
+type HistoryRecord struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Namespace      string             `bson:"namespace" json:"namespace"`
+	DeploymentName string             `bson:"deployment_name" json:"deployment_name"`
+	Action         string             `bson:"action" json:"action"`
+	Actor          Actor              `bson:"actor" json:"actor"`
+	Changes        []HistoryChange    `bson:"changes" json:"changes"`
+	Outcome        string             `bson:"outcome" json:"outcome"`
+	Error          string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// HistoryQuery holds the query parameters of the history api
+type HistoryQuery struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+type HistoryPage struct {
+	Records    []HistoryRecord `json:"records"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// historyFields flattens the image, replicas, resources and autoscaler of a deployment record, containers by name
+func historyFields(deployment *CreateDeploymentRequest) map[string]string {
+	values := map[string]string{}
+	if deployment == nil {
+		return values
+	}
+	values["image"] = deployment.Image
+	values["replicas"] = strconv.Itoa(int(deployment.Replicas))
+	addResources := func(prefix string, resources *DeploymentResources) {
+		if resources == nil {
+			return
+		}
+		values[prefix+"resources.requests.cpu"] = resources.Requests.CPU
+		values[prefix+"resources.requests.memory"] = resources.Requests.Memory
+		values[prefix+"resources.limits.cpu"] = resources.Limits.CPU
+		values[prefix+"resources.limits.memory"] = resources.Limits.Memory
+	}
+	addResources("", deployment.Resources)
+	if autoscaler := deployment.Autoscaler; autoscaler != nil {
+		values["autoscaler.min_replicas"] = strconv.Itoa(int(autoscaler.MinReplicas))
+		values["autoscaler.max_replicas"] = strconv.Itoa(int(autoscaler.MaxReplicas))
+		if autoscaler.TargetCPUUtilization != nil {
+			values["autoscaler.target_cpu_utilization"] = strconv.Itoa(int(*autoscaler.TargetCPUUtilization))
+		}
+		if autoscaler.TargetMemoryUtilization != nil {
+			values["autoscaler.target_memory_utilization"] = strconv.Itoa(int(*autoscaler.TargetMemoryUtilization))
+		}
+	}
+	for _, container := range deployment.Sidecars {
+		prefix := "sidecars." + container.Name + "."
+		values[prefix+"image"] = container.Image
+		addResources(prefix, container.Resources)
+	}
+	for _, container := range deployment.InitContainers {
+		prefix := "init_containers." + container.Name + "."
+		values[prefix+"image"] = container.Image
+		addResources(prefix, container.Resources)
+	}
+	return values
+}
+
+// DiffDeployments lists the image, replicas, resources and autoscaler fields that differ between two versions
+// of a deployment record, a nil record is a deployment that doesn't exist
+func DiffDeployments(before, after *CreateDeploymentRequest) []HistoryChange {
+	beforeValues, afterValues := historyFields(before), historyFields(after)
+	changes := []HistoryChange{}
+	for _, field := range sortedFields(beforeValues, afterValues) {
+		if beforeValues[field] != afterValues[field] {
+			changes = append(changes, HistoryChange{Field: field, Before: beforeValues[field], After: afterValues[field]})
+		}
+	}
+	return changes
+}
+
+// sortedFields returns the keys of both maps once, in order
+func sortedFields(maps ...map[string]string) []string {
+	seen := map[string]bool{}
+	var fields []string
+	for _, values := range maps {
+		for field := range values {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,62 @@
+package model_deployment
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDeployments(t *testing.T) {
+	cpu := int32(70)
+	before := &CreateDeploymentRequest{
+		Image:    "nginx:1",
+		Replicas: 2,
+		Resources: &DeploymentResources{
+			Requests: ResourceQuantities{CPU: "100m", Memory: "64Mi"},
+			Limits:   ResourceQuantities{CPU: "200m", Memory: "128Mi"},
+		},
+		Sidecars: []Container{{Name: "envoy", Image: "envoy:1"}},
+	}
+	after := &CreateDeploymentRequest{
+		Image:    "nginx:2",
+		Replicas: 2,
+		Resources: &DeploymentResources{
+			Requests: ResourceQuantities{CPU: "100m", Memory: "64Mi"},
+			Limits:   ResourceQuantities{CPU: "500m", Memory: "128Mi"},
+		},
+		Sidecars:   []Container{{Name: "envoy", Image: "envoy:2"}},
+		Autoscaler: &AutoscalerConfig{MinReplicas: 1, MaxReplicas: 4, TargetCPUUtilization: &cpu},
+	}
+
+	want := []HistoryChange{
+		{Field: "autoscaler.max_replicas", Before: "", After: "4"},
+		{Field: "autoscaler.min_replicas", Before: "", After: "1"},
+		{Field: "autoscaler.target_cpu_utilization", Before: "", After: "70"},
+		{Field: "image", Before: "nginx:1", After: "nginx:2"},
+		{Field: "resources.limits.cpu", Before: "200m", After: "500m"},
+		{Field: "sidecars.envoy.image", Before: "envoy:1", After: "envoy:2"},
+	}
+	if changes := DiffDeployments(before, after); !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %+v, got %+v", want, changes)
+	}
+}
+
+func TestDiffDeploymentsUnchanged(t *testing.T) {
+	deployment := &CreateDeploymentRequest{Image: "nginx:1", Replicas: 1}
+	if changes := DiffDeployments(deployment, deployment); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffDeploymentsCreateAndDelete(t *testing.T) {
+	deployment := &CreateDeploymentRequest{Image: "nginx:1", Replicas: 1}
+	want := []HistoryChange{
+		{Field: "image", Before: "", After: "nginx:1"},
+		{Field: "replicas", Before: "", After: "1"},
+	}
+	if changes := DiffDeployments(nil, deployment); !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %+v, got %+v", want, changes)
+	}
+	if changes := DiffDeployments(deployment, nil); len(changes) != 2 || changes[0].After != "" || changes[0].Before != "nginx:1" {
+		t.Errorf("expected the fields of a deleted deployment to go empty, got %+v", changes)
+	}
+}
@@ -0,0 +1,43 @@
+package model_deployment
+
+import (
+	"fmt"
+	"time"
+)
+
+// lifecycle states of a deployment, kept in the status field of its DEPLOYMENTS document
+const (
+	DEPLOYMENT_ACTIVE string = "ACTIVE"
+	// scaled to 0 by a pause, the previous replica count and status are kept in PausedState
+	DEPLOYMENT_PAUSED string = "PAUSED"
+	// the latest rollout failed or exceeded its progress deadline
+	DEPLOYMENT_FAILED string = "FAILED"
+	// the deployment and its resources are being removed
+	DEPLOYMENT_DELETING string = "DELETING"
+)
+
+// PausedState is what a resume restores
+type PausedState struct {
+	Replicas int32     `bson:"replicas" json:"replicas"`
+	Status   string    `bson:"status" json:"status"`
+	PausedAt time.Time `bson:"pausedAt" json:"pausedAt"`
+}
+
+// LifecycleStatus is the lifecycle state of the deployment, documents written before it was tracked are active
+func (d CreateDeploymentRequest) LifecycleStatus() string {
+	if d.Status == "" {
+		return DEPLOYMENT_ACTIVE
+	}
+	return d.Status
+}
+
+// CheckChangeable rejects changes to deployments that are paused or being deleted, action names the change in the error
+func (d CreateDeploymentRequest) CheckChangeable(action string) error {
+	switch d.LifecycleStatus() {
+	case DEPLOYMENT_PAUSED:
+		return fmt.Errorf("deployment %s is paused, resume it before you %s it", d.Name, action)
+	case DEPLOYMENT_DELETING:
+		return fmt.Errorf("deployment %s is being deleted, can't %s it", d.Name, action)
+	}
+	return nil
+}
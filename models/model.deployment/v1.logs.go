@@ -0,0 +1,20 @@
+package model_deployment
+
+// LogsQuery holds the query parameters of the deployment logs api
+type LogsQuery struct {
+	Pod          string `form:"pod"`
+	Container    string `form:"container"`
+	TailLines    *int64 `form:"tailLines"`
+	SinceSeconds *int64 `form:"sinceSeconds"`
+	Previous     bool   `form:"previous"`
+	Follow       bool   `form:"follow"`
+	Format       string `form:"format"`
+}
+
+type LogLine struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Line      string `json:"line"`
+	// Error is set on the last line of a pod when its log stream ended abnormally
+	Error string `json:"error,omitempty"`
+}
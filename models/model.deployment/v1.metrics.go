@@ -0,0 +1,19 @@
+package model_deployment
+
+// ResourceUtilization is the live usage of a set of pods compared with their requests and limits.
+// Source tells whether the usage comes from metrics-server or the fake provider.
+type ResourceUtilization struct {
+	Source string        `json:"source"`
+	Pods   int           `json:"pods"`
+	CPU    ResourceUsage `json:"cpu"`
+	Memory ResourceUsage `json:"memory"`
+}
+
+// ResourceUsage compares the usage of a resource with the requests and limits, percentages are left out without them
+type ResourceUsage struct {
+	Usage           string `json:"usage"`
+	Requests        string `json:"requests"`
+	Limits          string `json:"limits"`
+	RequestsPercent *int64 `json:"requests_percent,omitempty"`
+	LimitsPercent   *int64 `json:"limits_percent,omitempty"`
+}
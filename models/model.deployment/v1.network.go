@@ -0,0 +1,38 @@
+package model_deployment
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NetworkAccessRule opens ports of a deployment to a service of another tenant.
+// Service is the deployment name of the calling service, Ports default to the container port.
+type NetworkAccessRule struct {
+	Tenant  string  `bson:"tenant" json:"tenant"`
+	Service string  `bson:"service" json:"service"`
+	Ports   []int32 `bson:"ports" json:"ports"`
+}
+
+// ValidateNetworkAccess checks the tenant and service names and ports of every rule
+func ValidateNetworkAccess(rules []NetworkAccessRule) error {
+	for _, rule := range rules {
+		if rule.Tenant == "" || rule.Service == "" {
+			return errors.New("network access rules need a tenant and a service")
+		}
+		if errs := validation.IsDNS1123Label(rule.Tenant); len(errs) > 0 {
+			return fmt.Errorf("invalid tenant %q: %s", rule.Tenant, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsDNS1123Label(rule.Service); len(errs) > 0 {
+			return fmt.Errorf("invalid service %q: %s", rule.Service, strings.Join(errs, ", "))
+		}
+		for _, port := range rule.Ports {
+			if port < 1 || port > 65535 {
+				return fmt.Errorf("port %d of network access rule for %s/%s is out of range", port, rule.Tenant, rule.Service)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package model_deployment
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// kinds of multi-step operations
+const (
+	OPERATION_CREATE string = "CREATE"
+	OPERATION_DELETE string = "DELETE"
+)
+
+// states of an operation and of its steps
+const (
+	OPERATION_RUNNING   string = "RUNNING"
+	OPERATION_SUCCEEDED string = "SUCCEEDED"
+	// a step failed and every completed step was compensated, nothing is left half done
+	OPERATION_ROLLED_BACK string = "ROLLED_BACK"
+	// a step failed and so did the compensation of an earlier one, a retry cleans up what is left
+	OPERATION_FAILED string = "FAILED"
+
+	STEP_DONE                string = "DONE"
+	STEP_FAILED              string = "FAILED"
+	STEP_COMPENSATED         string = "COMPENSATED"
+	STEP_COMPENSATION_FAILED string = "COMPENSATION_FAILED"
+)
+
+// Operation records the steps of a create or delete in the OPERATIONS collection
+type Operation struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type           string             `bson:"type" json:"type"`
+	Namespace      string             `bson:"namespace" json:"namespace"`
+	DeploymentName string             `bson:"deployment_name" json:"deployment_name"`
+	Status         string             `bson:"status" json:"status"`
+	Steps          []OperationStep    `bson:"steps" json:"steps"`
+	Error          string             `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt      time.Time          `bson:"startedAt" json:"startedAt"`
+	FinishedAt     *time.Time         `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
+
+type OperationStep struct {
+	Name   string    `bson:"name" json:"name"`
+	Status string    `bson:"status" json:"status"`
+	Error  string    `bson:"error,omitempty" json:"error,omitempty"`
+	At     time.Time `bson:"at" json:"at"`
+}
@@ -0,0 +1,26 @@
+package model_deployment
+
+// PodInfo describes a pod selected by a deployment
+type PodInfo struct {
+	Name                  string               `json:"name"`
+	Phase                 string               `json:"phase"`
+	ReadyContainers       int                  `json:"ready_containers"`
+	TotalContainers       int                  `json:"total_containers"`
+	Restarts              int32                `json:"restarts"`
+	LastTerminationReason string               `json:"last_termination_reason,omitempty"`
+	Node                  string               `json:"node"`
+	PodIP                 string               `json:"pod_ip"`
+	Age                   string               `json:"age"`
+	Containers            []PodContainerStatus `json:"containers"`
+}
+
+// PodContainerStatus is the state of a single container in a pod, init containers included
+type PodContainerStatus struct {
+	Name                  string `json:"name"`
+	Init                  bool   `json:"init"`
+	Ready                 bool   `json:"ready"`
+	RestartCount          int32  `json:"restart_count"`
+	State                 string `json:"state"`
+	Reason                string `json:"reason,omitempty"`
+	LastTerminationReason string `json:"last_termination_reason,omitempty"`
+}
@@ -0,0 +1,155 @@
+package model_deployment
+
+import (
+	"deployment-service/constants"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	PROBE_HTTP string = "http"
+	PROBE_TCP  string = "tcp"
+	PROBE_EXEC string = "exec"
+)
+
+type Probe struct {
+	Type                string   `bson:"type" json:"type"`
+	Path                string   `bson:"path,omitempty" json:"path,omitempty"`
+	Port                int32    `bson:"port,omitempty" json:"port,omitempty"`
+	Command             []string `bson:"command,omitempty" json:"command,omitempty"`
+	InitialDelaySeconds int32    `bson:"initial_delay_seconds" json:"initial_delay_seconds"`
+	PeriodSeconds       int32    `bson:"period_seconds" json:"period_seconds"`
+	TimeoutSeconds      int32    `bson:"timeout_seconds" json:"timeout_seconds"`
+	FailureThreshold    int32    `bson:"failure_threshold" json:"failure_threshold"`
+}
+
+// DeploymentProbes configures the container probes, a nil probe is not set on the container
+type DeploymentProbes struct {
+	Liveness  *Probe `bson:"liveness,omitempty" json:"liveness,omitempty"`
+	Readiness *Probe `bson:"readiness,omitempty" json:"readiness,omitempty"`
+	Startup   *Probe `bson:"startup,omitempty" json:"startup,omitempty"`
+}
+
+// DefaultDeploymentProbes are the probes of a deployment created without any. With DEFAULT_PROBES_ENABLED
+// they check that the container accepts connections on its port, otherwise there are none.
+func DefaultDeploymentProbes(containerPort int32) DeploymentProbes {
+	if !constants.DEFAULT_PROBES_ENABLED {
+		return DeploymentProbes{}
+	}
+	return DeploymentProbes{
+		Readiness: &Probe{Type: PROBE_TCP, Port: containerPort},
+		Liveness:  &Probe{Type: PROBE_TCP, Port: containerPort, InitialDelaySeconds: 15},
+	}.WithDefaults(containerPort)
+}
+
+// WithDefaults fills the unset probe fields, ports default to the container port
+func (p DeploymentProbes) WithDefaults(containerPort int32) DeploymentProbes {
+	for _, probe := range []**Probe{&p.Liveness, &p.Readiness, &p.Startup} {
+		if *probe == nil {
+			continue
+		}
+		withDefaults := **probe
+		if withDefaults.Type != PROBE_EXEC && withDefaults.Port == 0 {
+			withDefaults.Port = containerPort
+		}
+		if withDefaults.Type == PROBE_HTTP && withDefaults.Path == "" {
+			withDefaults.Path = "/"
+		}
+		if withDefaults.PeriodSeconds == 0 {
+			withDefaults.PeriodSeconds = 10
+		}
+		if withDefaults.TimeoutSeconds == 0 {
+			withDefaults.TimeoutSeconds = 1
+		}
+		if withDefaults.FailureThreshold == 0 {
+			withDefaults.FailureThreshold = 3
+		}
+		*probe = &withDefaults
+	}
+	return p
+}
+
+// Validate checks every configured probe has what its type needs
+func (p DeploymentProbes) Validate() error {
+	probes := map[string]*Probe{"liveness": p.Liveness, "readiness": p.Readiness, "startup": p.Startup}
+	for name, probe := range probes {
+		if probe == nil {
+			continue
+		}
+		switch probe.Type {
+		case PROBE_HTTP, PROBE_TCP:
+			if probe.Port < 0 || probe.Port > 65535 {
+				return fmt.Errorf("%s probe port %d is out of range", name, probe.Port)
+			}
+		case PROBE_EXEC:
+			if len(probe.Command) == 0 {
+				return fmt.Errorf("%s probe of type exec needs a command", name)
+			}
+		default:
+			return fmt.Errorf("%s probe type must be one of http, tcp or exec", name)
+		}
+		if probe.InitialDelaySeconds < 0 || probe.PeriodSeconds < 0 || probe.TimeoutSeconds < 0 || probe.FailureThreshold < 0 {
+			return errors.New(name + " probe timings must not be negative")
+		}
+	}
+	return nil
+}
+
+// KubernetesProbe converts a probe to the kubernetes container probe
+func (probe *Probe) KubernetesProbe() *corev1.Probe {
+	if probe == nil {
+		return nil
+	}
+	k8sProbe := &corev1.Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+	switch probe.Type {
+	case PROBE_HTTP:
+		k8sProbe.HTTPGet = &corev1.HTTPGetAction{Path: probe.Path, Port: intstr.FromInt32(probe.Port)}
+	case PROBE_TCP:
+		k8sProbe.TCPSocket = &corev1.TCPSocketAction{Port: intstr.FromInt32(probe.Port)}
+	case PROBE_EXEC:
+		k8sProbe.Exec = &corev1.ExecAction{Command: probe.Command}
+	}
+	return k8sProbe
+}
+
+// ProbesFromContainer reads the probes configured on a kubernetes container
+func ProbesFromContainer(container corev1.Container) DeploymentProbes {
+	return DeploymentProbes{
+		Liveness:  probeFromKubernetes(container.LivenessProbe),
+		Readiness: probeFromKubernetes(container.ReadinessProbe),
+		Startup:   probeFromKubernetes(container.StartupProbe),
+	}
+}
+
+func probeFromKubernetes(k8sProbe *corev1.Probe) *Probe {
+	if k8sProbe == nil {
+		return nil
+	}
+	probe := &Probe{
+		InitialDelaySeconds: k8sProbe.InitialDelaySeconds,
+		PeriodSeconds:       k8sProbe.PeriodSeconds,
+		TimeoutSeconds:      k8sProbe.TimeoutSeconds,
+		FailureThreshold:    k8sProbe.FailureThreshold,
+	}
+	switch {
+	case k8sProbe.HTTPGet != nil:
+		probe.Type = PROBE_HTTP
+		probe.Path = k8sProbe.HTTPGet.Path
+		probe.Port = k8sProbe.HTTPGet.Port.IntVal
+	case k8sProbe.TCPSocket != nil:
+		probe.Type = PROBE_TCP
+		probe.Port = k8sProbe.TCPSocket.Port.IntVal
+	case k8sProbe.Exec != nil:
+		probe.Type = PROBE_EXEC
+		probe.Command = k8sProbe.Exec.Command
+	}
+	return probe
+}
@@ -0,0 +1,123 @@
+package model_deployment
+
+import (
+	"deployment-service/constants"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type ResourceQuantities struct {
+	CPU    string `bson:"cpu" json:"cpu"`
+	Memory string `bson:"memory" json:"memory"`
+}
+
+type DeploymentResources struct {
+	Requests ResourceQuantities `bson:"requests" json:"requests"`
+	Limits   ResourceQuantities `bson:"limits" json:"limits"`
+}
+
+// DefaultDeploymentResources returns the resources applied when a deployment doesn't specify any
+func DefaultDeploymentResources() DeploymentResources {
+	return DeploymentResources{
+		Requests: ResourceQuantities{CPU: constants.DEFAULT_REQUEST_CPU, Memory: constants.DEFAULT_REQUEST_MEMORY},
+		Limits:   ResourceQuantities{CPU: constants.DEFAULT_LIMIT_CPU, Memory: constants.DEFAULT_LIMIT_MEMORY},
+	}
+}
+
+// ContainerResources returns the current resources of the named container of a deployment, the main container
+// when name is empty. Partial resource updates are merged with them.
+func (d *CreateDeploymentRequest) ContainerResources(name string) DeploymentResources {
+	if name == "" || name == d.Name {
+		if d.Resources != nil {
+			return *d.Resources
+		}
+		// Deployments created before resources were configurable run with the defaults
+		return DefaultDeploymentResources()
+	}
+	for _, container := range append(append([]Container{}, d.Sidecars...), d.InitContainers...) {
+		if container.Name == name && container.Resources != nil {
+			return *container.Resources
+		}
+	}
+	return DeploymentResources{}
+}
+
+// ResourcesFromRequirements converts kubernetes container resources back to the deployment resources
+func ResourcesFromRequirements(requirements corev1.ResourceRequirements) DeploymentResources {
+	var resources DeploymentResources
+	if cpu, ok := requirements.Requests[corev1.ResourceCPU]; ok {
+		resources.Requests.CPU = cpu.String()
+	}
+	if memory, ok := requirements.Requests[corev1.ResourceMemory]; ok {
+		resources.Requests.Memory = memory.String()
+	}
+	if cpu, ok := requirements.Limits[corev1.ResourceCPU]; ok {
+		resources.Limits.CPU = cpu.String()
+	}
+	if memory, ok := requirements.Limits[corev1.ResourceMemory]; ok {
+		resources.Limits.Memory = memory.String()
+	}
+	return resources
+}
+
+// Merge fills the quantities missing from r with the ones from base
+func (r DeploymentResources) Merge(base DeploymentResources) DeploymentResources {
+	if r.Requests.CPU == "" {
+		r.Requests.CPU = base.Requests.CPU
+	}
+	if r.Requests.Memory == "" {
+		r.Requests.Memory = base.Requests.Memory
+	}
+	if r.Limits.CPU == "" {
+		r.Limits.CPU = base.Limits.CPU
+	}
+	if r.Limits.Memory == "" {
+		r.Limits.Memory = base.Limits.Memory
+	}
+	return r
+}
+
+// ResourceRequirements validates the quantities and converts them to the kubernetes container resources.
+// Limits must be greater than or equal to the requests.
+func (r DeploymentResources) ResourceRequirements() (corev1.ResourceRequirements, error) {
+	var requirements = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+	quantities := []struct {
+		field string
+		value string
+		list  corev1.ResourceList
+		name  corev1.ResourceName
+	}{
+		{"requests.cpu", r.Requests.CPU, requirements.Requests, corev1.ResourceCPU},
+		{"requests.memory", r.Requests.Memory, requirements.Requests, corev1.ResourceMemory},
+		{"limits.cpu", r.Limits.CPU, requirements.Limits, corev1.ResourceCPU},
+		{"limits.memory", r.Limits.Memory, requirements.Limits, corev1.ResourceMemory},
+	}
+	for _, q := range quantities {
+		if q.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return requirements, fmt.Errorf("invalid %s %q: %w", q.field, q.value, err)
+		}
+		if quantity.Sign() <= 0 {
+			return requirements, fmt.Errorf("invalid %s %q: must be greater than zero", q.field, q.value)
+		}
+		q.list[q.name] = quantity
+	}
+
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := requirements.Requests[name]
+		limit, hasLimit := requirements.Limits[name]
+		if hasRequest && hasLimit && limit.Cmp(request) < 0 {
+			return requirements, fmt.Errorf("limits.%s %s must be greater than or equal to requests.%s %s",
+				name, limit.String(), name, request.String())
+		}
+	}
+	return requirements, nil
+}
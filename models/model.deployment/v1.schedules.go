@@ -0,0 +1,106 @@
+package model_deployment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// outcomes recorded when a schedule fires
+const (
+	SCHEDULE_RUN_SUCCESS string = "SUCCESS"
+	SCHEDULE_RUN_FAILED  string = "FAILED"
+)
+
+// ScheduleReq creates or replaces a scaling schedule. Cron is a standard five field expression
+// evaluated in Timezone, UTC when it is empty.
+type ScheduleReq struct {
+	Cron     string `json:"cron"`
+	Timezone string `json:"timezone"`
+	Replicas int32  `json:"replicas"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+// Schedule scales a deployment to Replicas every time Cron fires, stored in the SCHEDULES collection
+type Schedule struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Namespace      string             `bson:"namespace" json:"namespace"`
+	DeploymentName string             `bson:"deployment_name" json:"deployment_name"`
+	Cron           string             `bson:"cron" json:"cron"`
+	Timezone       string             `bson:"timezone" json:"timezone"`
+	Replicas       int32              `bson:"replicas" json:"replicas"`
+	Enabled        bool               `bson:"enabled" json:"enabled"`
+	LastRunAt      *time.Time         `bson:"lastRunAt,omitempty" json:"lastRunAt,omitempty"`
+	LastOutcome    string             `bson:"last_outcome,omitempty" json:"last_outcome,omitempty"`
+	NextRunAt      *time.Time         `bson:"-" json:"nextRunAt,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ScheduleRun is the audit entry written to SCHEDULE_RUNS each time a schedule fires
+type ScheduleRun struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ScheduleID     primitive.ObjectID `bson:"schedule_id" json:"schedule_id"`
+	Namespace      string             `bson:"namespace" json:"namespace"`
+	DeploymentName string             `bson:"deployment_name" json:"deployment_name"`
+	Cron           string             `bson:"cron" json:"cron"`
+	Replicas       int32              `bson:"replicas" json:"replicas"`
+	Outcome        string             `bson:"outcome" json:"outcome"`
+	Error          string             `bson:"error,omitempty" json:"error,omitempty"`
+	FiredAt        time.Time          `bson:"firedAt" json:"firedAt"`
+}
+
+// ScheduleSpec is the cron spec of the schedule with its timezone
+func ScheduleSpec(expression, timezone string) string {
+	if timezone == "" {
+		return expression
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", timezone, expression)
+}
+
+// ParseSchedule validates the cron expression and timezone
+func ParseSchedule(expression, timezone string) (cron.Schedule, error) {
+	if expression == "" {
+		return nil, errors.New("cron is required")
+	}
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+	}
+	schedule, err := cron.ParseStandard(ScheduleSpec(expression, timezone))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron %q: %w", expression, err)
+	}
+	return schedule, nil
+}
+
+// Validate checks the cron expression, the timezone and the replica count
+func (r ScheduleReq) Validate() error {
+	if r.Replicas < 0 {
+		return errors.New("replicas must not be negative")
+	}
+	_, err := ParseSchedule(r.Cron, r.Timezone)
+	return err
+}
+
+// NextRuns previews the next count times the schedule fires after from
+func (s Schedule) NextRuns(from time.Time, count int) ([]time.Time, error) {
+	schedule, err := ParseSchedule(s.Cron, s.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	runs := []time.Time{}
+	next := from
+	for i := 0; i < count; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+	}
+	return runs, nil
+}
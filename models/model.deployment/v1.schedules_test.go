@@ -0,0 +1,80 @@
+package model_deployment
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestScheduleReqValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		req  ScheduleReq
+		ok   bool
+	}{
+		{"utc", ScheduleReq{Cron: "0 9 * * 1-5", Replicas: 3}, true},
+		{"timezone", ScheduleReq{Cron: "0 9 * * 1-5", Timezone: "Europe/Berlin", Replicas: 3}, true},
+		{"scale to zero", ScheduleReq{Cron: "0 20 * * *", Replicas: 0}, true},
+		{"missing cron", ScheduleReq{Replicas: 3}, false},
+		{"invalid cron", ScheduleReq{Cron: "0 9 * *", Replicas: 3}, false},
+		{"seconds field", ScheduleReq{Cron: "0 0 9 * * 1-5", Replicas: 3}, false},
+		{"invalid timezone", ScheduleReq{Cron: "0 9 * * *", Timezone: "Mars/Olympus", Replicas: 3}, false},
+		{"negative replicas", ScheduleReq{Cron: "0 9 * * *", Replicas: -1}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.req.Validate(); (err == nil) != tc.ok {
+				t.Errorf("expected ok=%v, got %v", tc.ok, err)
+			}
+		})
+	}
+}
+
+func TestNextRuns(t *testing.T) {
+	from := time.Date(2026, time.March, 27, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		schedule Schedule
+		want     []time.Time
+	}{
+		{
+			name:     "utc",
+			schedule: Schedule{Cron: "0 9 * * 1-5"},
+			want: []time.Time{
+				time.Date(2026, time.March, 27, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, time.March, 30, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Berlin switches to summer time on 29 March, 9:00 moves from 8:00 to 7:00 UTC
+			name:     "timezone across daylight saving",
+			schedule: Schedule{Cron: "0 9 * * 1-5", Timezone: "Europe/Berlin"},
+			want: []time.Time{
+				time.Date(2026, time.March, 27, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, time.March, 30, 7, 0, 0, 0, time.UTC),
+				time.Date(2026, time.March, 31, 7, 0, 0, 0, time.UTC),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			runs, err := tc.schedule.NextRuns(from, len(tc.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(runs) != len(tc.want) {
+				t.Fatalf("expected %d runs, got %v", len(tc.want), runs)
+			}
+			for i, run := range runs {
+				if !run.Equal(tc.want[i]) {
+					t.Errorf("run %d: expected %s, got %s", i, tc.want[i], run.UTC())
+				}
+			}
+		})
+	}
+}
+
+func TestNextRunsInvalidSchedule(t *testing.T) {
+	if _, err := (Schedule{Cron: "not a cron"}).NextRuns(time.Now(), 3); err == nil {
+		t.Error("expected an invalid cron to fail")
+	}
+}
@@ -0,0 +1,51 @@
+package model_deployment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DeploymentSpecReq is the full desired spec of the deployment's pods. Everything it declares is replaced,
+// left out fields fall back to the same defaults a create applies.
+// The container port and exposure are fixed at create, network access and the autoscaler have their own
+// endpoints; a spec declaring them is rejected instead of silently leaving them unchanged.
+type DeploymentSpecReq struct {
+	Image string `json:"image"`
+	// Replicas is left out of the spec of an autoscaled deployment, its autoscaler owns the replica count
+	Replicas         *int32               `json:"replicas"`
+	Resources        *DeploymentResources `json:"resources"`
+	Env              []EnvVar             `json:"env"`
+	Probes           *DeploymentProbes    `json:"probes"`
+	Sidecars         []Container          `json:"sidecars"`
+	InitContainers   []Container          `json:"init_containers"`
+	ImagePullSecrets []string             `json:"image_pull_secrets"`
+
+	ContainerPort json.RawMessage `json:"container_port,omitempty"`
+	Exposure      json.RawMessage `json:"exposure,omitempty"`
+	NetworkAccess json.RawMessage `json:"network_access,omitempty"`
+	Autoscaler    json.RawMessage `json:"autoscaler,omitempty"`
+}
+
+func (req *DeploymentSpecReq) Validate() error {
+	if req.Image == "" {
+		return errors.New("image is required")
+	}
+	if req.Replicas != nil && *req.Replicas < 0 {
+		return errors.New("replicas can't be negative")
+	}
+	for _, field := range []struct {
+		name  string
+		value json.RawMessage
+	}{
+		{"container_port", req.ContainerPort},
+		{"exposure", req.Exposure},
+		{"network_access", req.NetworkAccess},
+		{"autoscaler", req.Autoscaler},
+	} {
+		if len(field.value) > 0 {
+			return fmt.Errorf("%s isn't part of the deployment spec", field.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package model_deployment
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDeploymentSpecReqValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+		ok   bool
+	}{
+		{"image only", `{"image": "nginx:1"}`, true},
+		{"replicas", `{"image": "nginx:1", "replicas": 2}`, true},
+		{"missing image", `{"replicas": 2}`, false},
+		{"negative replicas", `{"image": "nginx:1", "replicas": -1}`, false},
+		{"container port", `{"image": "nginx:1", "container_port": 8080}`, false},
+		{"exposure", `{"image": "nginx:1", "exposure": {"type": "ingress"}}`, false},
+		{"network access", `{"image": "nginx:1", "network_access": {}}`, false},
+		{"autoscaler", `{"image": "nginx:1", "autoscaler": {"min_replicas": 1}}`, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req DeploymentSpecReq
+			if err := json.Unmarshal([]byte(tc.body), &req); err != nil {
+				t.Fatal(err)
+			}
+			if err := req.Validate(); (err == nil) != tc.ok {
+				t.Errorf("expected ok=%v, got %v", tc.ok, err)
+			}
+		})
+	}
+}
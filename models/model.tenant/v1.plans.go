@@ -0,0 +1,38 @@
+package model_tenant
+
+import "deployment-service/constants"
+
+// TENANT_PLANS holds the quota and container defaults a tenant namespace starts with
+var TENANT_PLANS = map[string]TenantPlan{
+	"free": {
+		Quota: TenantQuota{
+			Pods: 10, RequestsCPU: "1", RequestsMemory: "2Gi", LimitsCPU: "2", LimitsMemory: "4Gi",
+			Services: 5, LoadBalancers: 1,
+		},
+		LimitRange: defaultLimitRange(),
+	},
+	"standard": {
+		Quota: TenantQuota{
+			Pods: 50, RequestsCPU: "4", RequestsMemory: "8Gi", LimitsCPU: "8", LimitsMemory: "16Gi",
+			Services: 20, LoadBalancers: 3,
+		},
+		LimitRange: defaultLimitRange(),
+	},
+	"premium": {
+		Quota: TenantQuota{
+			Pods: 200, RequestsCPU: "16", RequestsMemory: "32Gi", LimitsCPU: "32", LimitsMemory: "64Gi",
+			Services: 100, LoadBalancers: 10,
+		},
+		LimitRange: defaultLimitRange(),
+	},
+}
+
+// defaultLimitRange uses the same defaults deployments get when they don't set resources
+func defaultLimitRange() TenantLimitRange {
+	return TenantLimitRange{
+		DefaultRequestCPU:    constants.DEFAULT_REQUEST_CPU,
+		DefaultRequestMemory: constants.DEFAULT_REQUEST_MEMORY,
+		DefaultLimitCPU:      constants.DEFAULT_LIMIT_CPU,
+		DefaultLimitMemory:   constants.DEFAULT_LIMIT_MEMORY,
+	}
+}
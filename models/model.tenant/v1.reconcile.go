@@ -0,0 +1,25 @@
+package model_tenant
+
+import "fmt"
+
+// reconcile policies deciding what the reconciler does with the drift of a tenant's deployments
+const (
+	// drift is only reported
+	RECONCILE_REPORT string = "REPORT"
+	// the DEPLOYMENTS records are the source of truth, the cluster is changed to match them
+	RECONCILE_CLUSTER string = "CLUSTER"
+	// the cluster is the source of truth, the DEPLOYMENTS records are changed to match it
+	RECONCILE_DATABASE string = "DATABASE"
+)
+
+type UpdateReconcilePolicyReq struct {
+	Policy string `json:"policy"`
+}
+
+func ValidateReconcilePolicy(policy string) error {
+	switch policy {
+	case RECONCILE_REPORT, RECONCILE_CLUSTER, RECONCILE_DATABASE:
+		return nil
+	}
+	return fmt.Errorf("unknown reconcile policy %q, expected one of %s, %s or %s", policy, RECONCILE_REPORT, RECONCILE_CLUSTER, RECONCILE_DATABASE)
+}
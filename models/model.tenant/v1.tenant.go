@@ -0,0 +1,172 @@
+package model_tenant
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TenantQuota is the ResourceQuota applied to a tenant namespace, zero values are left out of the quota
+type TenantQuota struct {
+	Pods           int64  `bson:"pods" json:"pods"`
+	RequestsCPU    string `bson:"requests_cpu" json:"requests_cpu"`
+	RequestsMemory string `bson:"requests_memory" json:"requests_memory"`
+	LimitsCPU      string `bson:"limits_cpu" json:"limits_cpu"`
+	LimitsMemory   string `bson:"limits_memory" json:"limits_memory"`
+	Services       int64  `bson:"services" json:"services"`
+	LoadBalancers  int64  `bson:"load_balancers" json:"load_balancers"`
+}
+
+// TenantLimitRange holds the container defaults applied to pods that don't set their own resources
+type TenantLimitRange struct {
+	DefaultRequestCPU    string `bson:"default_request_cpu" json:"default_request_cpu"`
+	DefaultRequestMemory string `bson:"default_request_memory" json:"default_request_memory"`
+	DefaultLimitCPU      string `bson:"default_limit_cpu" json:"default_limit_cpu"`
+	DefaultLimitMemory   string `bson:"default_limit_memory" json:"default_limit_memory"`
+}
+
+type TenantPlan struct {
+	Quota      TenantQuota      `json:"quota"`
+	LimitRange TenantLimitRange `json:"limit_range"`
+}
+
+type Tenant struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Namespace       string             `bson:"namespace" json:"namespace"`
+	Plan            string             `bson:"plan" json:"plan"`
+	Quota           TenantQuota        `bson:"quota" json:"quota"`
+	LimitRange      TenantLimitRange   `bson:"limit_range" json:"limit_range"`
+	ReconcilePolicy string             `bson:"reconcile_policy,omitempty" json:"reconcile_policy,omitempty"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// UpdateTenantQuotaReq switches a tenant to a plan and/or overrides single quota and limit range values
+type UpdateTenantQuotaReq struct {
+	Plan       string            `json:"plan"`
+	Quota      *TenantQuota      `json:"quota"`
+	LimitRange *TenantLimitRange `json:"limit_range"`
+}
+
+type TenantQuotaUsage struct {
+	Resource string `json:"resource"`
+	Used     string `json:"used"`
+	Hard     string `json:"hard"`
+}
+
+// Merge overrides the values of q with the non zero values of override
+func (q TenantQuota) Merge(override TenantQuota) TenantQuota {
+	if override.Pods != 0 {
+		q.Pods = override.Pods
+	}
+	if override.RequestsCPU != "" {
+		q.RequestsCPU = override.RequestsCPU
+	}
+	if override.RequestsMemory != "" {
+		q.RequestsMemory = override.RequestsMemory
+	}
+	if override.LimitsCPU != "" {
+		q.LimitsCPU = override.LimitsCPU
+	}
+	if override.LimitsMemory != "" {
+		q.LimitsMemory = override.LimitsMemory
+	}
+	if override.Services != 0 {
+		q.Services = override.Services
+	}
+	if override.LoadBalancers != 0 {
+		q.LoadBalancers = override.LoadBalancers
+	}
+	return q
+}
+
+// Merge overrides the values of l with the non empty values of override
+func (l TenantLimitRange) Merge(override TenantLimitRange) TenantLimitRange {
+	if override.DefaultRequestCPU != "" {
+		l.DefaultRequestCPU = override.DefaultRequestCPU
+	}
+	if override.DefaultRequestMemory != "" {
+		l.DefaultRequestMemory = override.DefaultRequestMemory
+	}
+	if override.DefaultLimitCPU != "" {
+		l.DefaultLimitCPU = override.DefaultLimitCPU
+	}
+	if override.DefaultLimitMemory != "" {
+		l.DefaultLimitMemory = override.DefaultLimitMemory
+	}
+	return l
+}
+
+// parseQuantities parses the named quantities into a resource list, empty values are skipped
+func parseQuantities(quantities map[corev1.ResourceName]string) (corev1.ResourceList, error) {
+	list := corev1.ResourceList{}
+	for name, value := range quantities {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+		list[name] = quantity
+	}
+	return list, nil
+}
+
+// Hard converts the quota to the hard limits of a ResourceQuota
+func (q TenantQuota) Hard() (corev1.ResourceList, error) {
+	hard, err := parseQuantities(map[corev1.ResourceName]string{
+		corev1.ResourceRequestsCPU:    q.RequestsCPU,
+		corev1.ResourceRequestsMemory: q.RequestsMemory,
+		corev1.ResourceLimitsCPU:      q.LimitsCPU,
+		corev1.ResourceLimitsMemory:   q.LimitsMemory,
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := map[corev1.ResourceName]int64{
+		corev1.ResourcePods:                  q.Pods,
+		corev1.ResourceServices:              q.Services,
+		corev1.ResourceServicesLoadBalancers: q.LoadBalancers,
+	}
+	for name, count := range counts {
+		if count < 0 {
+			return nil, fmt.Errorf("invalid %s %d: must not be negative", name, count)
+		}
+		if count > 0 {
+			hard[name] = *resource.NewQuantity(count, resource.DecimalSI)
+		}
+	}
+	return hard, nil
+}
+
+// ContainerLimits converts the limit range to the container defaults of a LimitRange
+func (l TenantLimitRange) ContainerLimits() (corev1.LimitRangeItem, error) {
+	defaultRequest, err := parseQuantities(map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    l.DefaultRequestCPU,
+		corev1.ResourceMemory: l.DefaultRequestMemory,
+	})
+	if err != nil {
+		return corev1.LimitRangeItem{}, err
+	}
+	defaultLimit, err := parseQuantities(map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    l.DefaultLimitCPU,
+		corev1.ResourceMemory: l.DefaultLimitMemory,
+	})
+	if err != nil {
+		return corev1.LimitRangeItem{}, err
+	}
+	for name, request := range defaultRequest {
+		if limit, ok := defaultLimit[name]; ok && limit.Cmp(request) < 0 {
+			return corev1.LimitRangeItem{}, fmt.Errorf("default limit %s must be greater than or equal to the default request", name)
+		}
+	}
+	return corev1.LimitRangeItem{
+		Type:           corev1.LimitTypeContainer,
+		DefaultRequest: defaultRequest,
+		Default:        defaultLimit,
+	}, nil
+}